@@ -0,0 +1,161 @@
+package best
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gilramir/paasaathai"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// Write a zip file of the corpus files, which are named by their paths
+func writeZip(c *C, files map[string]string) string {
+	path := filepath.Join(c.MkDir(), "test.zip")
+	fh, err := os.Create(path)
+	c.Assert(err, IsNil)
+	zw := zip.NewWriter(fh)
+	for name, text := range files {
+		w, err := zw.Create(name)
+		c.Assert(err, IsNil)
+		_, err = w.Write([]byte(text))
+		c.Assert(err, IsNil)
+	}
+	c.Assert(zw.Close(), IsNil)
+	c.Assert(fh.Close(), IsNil)
+	return path
+}
+
+func (s *MySuite) TestParseLine(c *C) {
+	text, words := ParseLine("<NE>กรุง|เทพ</NE>|เป็น| |<AB>ก.ค.</AB>|")
+	c.Check(text, Equals, "กรุงเทพเป็น ก.ค.")
+	c.Check(words, DeepEquals, []Word{
+		{Text: "กรุง", Tag: "NE", StartByte: 0, EndByte: 12, Item: 1},
+		{Text: "เทพ", Tag: "NE", StartByte: 12, EndByte: 21, Item: 2},
+		{Text: "เป็น", StartByte: 21, EndByte: 33, Item: 3},
+		{Text: " ", StartByte: 33, EndByte: 34, Item: 4},
+		{Text: "ก.ค.", Tag: "AB", StartByte: 34, EndByte: 42, Item: 5},
+	})
+}
+
+func (s *MySuite) TestReadZip(c *C) {
+	path := writeZip(c, map[string]string{
+		"news/news_00002.txt": "ข่าว|\r\n",
+		"news/news_00001.txt": "กา|\nมา|ดู|\n",
+		"news/README":         "not read",
+		// TIS-620
+		"news/news_00003.txt": "\xa1\xd2|",
+	})
+
+	var refs []string
+	var texts []string
+	err := ReadZip(path, func(line *Line) error {
+		refs = append(refs, line.Ref(len(line.Words)))
+		texts = append(texts, line.Text)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Check(refs, DeepEquals, []string{
+		path + "(news/news_00001.txt) line 1 item 1",
+		path + "(news/news_00001.txt) line 2 item 2",
+		path + "(news/news_00002.txt) line 1 item 1",
+		path + "(news/news_00003.txt) line 1 item 1",
+	})
+	c.Check(texts, DeepEquals, []string{"กา", "มาดู", "ข่าว", "กา"})
+}
+
+func (s *MySuite) TestEvaluateLine(c *C) {
+	line := &Line{Zip: "test.zip", File: "test.txt", Number: 7}
+	// The corpus splits เกา, which is one cluster
+	line.Text, line.Words = ParseLine("กา| |ุ|เก|า|")
+
+	var e Evaluator
+	report := &Report{}
+	e.EvaluateLine(line, report)
+
+	c.Check(report.Lines, Equals, 1)
+	c.Check(report.Words, Equals, 5)
+	c.Check(report.Clusters, Equals, 4)
+	c.Check(report.ClusterBoundaries, Equals, Score{
+		TruePositives:  3,
+		FalseNegatives: 1,
+	})
+	c.Check(report.WordBoundaries, IsNil)
+	c.Check(report.Invalid, DeepEquals, []Finding{
+		{Ref: "test.zip(test.txt) line 7 item 3", Text: "ุ", Word: "ุ",
+			Reason: "SoloDiacritic"},
+	})
+	c.Check(report.Crossing, DeepEquals, []Finding{
+		{Ref: "test.zip(test.txt) line 7 item 4", Text: "เกา", Word: "เก"},
+	})
+}
+
+func (s *MySuite) TestScore(c *C) {
+	var score Score
+	score.add([]int{1, 3, 5}, []int{3, 4, 5, 6})
+	c.Check(score, Equals, Score{TruePositives: 2, FalsePositives: 1, FalseNegatives: 2})
+	c.Check(score.Precision(), Equals, 2.0/3.0)
+	c.Check(score.Recall(), Equals, 0.5)
+}
+
+func (s *MySuite) TestCompareReports(c *C) {
+	path := writeZip(c, map[string]string{
+		"novel/novel_00001.txt": "กา| |ุ|\nเก|า|\n",
+	})
+	var e Evaluator
+	report, err := e.EvaluateZips(path)
+	c.Assert(err, IsNil)
+
+	saved := filepath.Join(c.MkDir(), "report.json")
+	c.Assert(report.Save(saved), IsNil)
+	old, err := LoadReport(saved)
+	c.Assert(err, IsNil)
+	c.Check(old, DeepEquals, report)
+
+	comparison := CompareReports(old, report)
+	c.Check(comparison.Regressed(), Equals, false)
+
+	// Pretend that the crossing cluster is new, and that an invalid
+	// cluster was fixed
+	old.Crossing = nil
+	old.ClusterBoundaries.TruePositives++
+	old.ClusterBoundaries.FalseNegatives--
+	old.Invalid = append(old.Invalid, Finding{Ref: "x", Text: "ิ", Reason: "SoloDiacritic"})
+
+	comparison = CompareReports(old, report)
+	c.Check(comparison.Regressed(), Equals, true)
+	c.Check(comparison.ScoresWorse, Equals, true)
+	c.Check(comparison.NewCrossing, DeepEquals, report.Crossing)
+	c.Check(comparison.NewInvalid, IsNil)
+	c.Check(comparison.FixedInvalid, DeepEquals,
+		[]Finding{{Ref: "x", Text: "ิ", Reason: "SoloDiacritic"}})
+
+	var sb strings.Builder
+	c.Assert(comparison.WriteText(&sb), IsNil)
+	c.Check(sb.String(), Matches, "(?s)The precision or recall is worse\n.*"+
+		"1 new clusters crossing word boundaries:\n  เกา in .*")
+}
+
+func (s *MySuite) TestEvaluateLineStats(c *C) {
+	line := &Line{Zip: "test.zip", File: "test.txt", Number: 7}
+	line.Text, line.Words = ParseLine("กา| |ุ|")
+
+	e := Evaluator{Stats: paasaathai.NewRuleStats(nil)}
+	e.EvaluateLine(line, &Report{})
+	c.Check(e.Stats.Clusters, Equals, 2)
+	c.Check(e.Stats.Rules["sara_a_aa"].Samples, DeepEquals, []paasaathai.RuleSample{
+		{Ref: "test.zip(test.txt) line 7", Text: "กา", Context: "[กา] ุ"},
+	})
+	c.Check(e.Stats.Reasons[paasaathai.ReasonSoloDiacritic].Count, Equals, 1)
+}
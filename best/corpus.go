@@ -0,0 +1,202 @@
+// Package best evaluates the parsers against the BEST corpus, which is
+// Thai text from NECTEC with the word boundaries marked by hand.
+//
+// The corpus comes as zip files, one per genre: article.zip,
+// encyclopedia.zip, news.zip, and novel.zip. Each holds text files in
+// which the words are separated by "|", and named entities,
+// abbreviations, and poems are tagged, as in
+//
+//	<NE>กรุงเทพ</NE>|เป็น|เมือง|หลวง|
+package best
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A word of the corpus
+type Word struct {
+	Text string
+
+	// "NE" for a named entity, "AB" for an abbreviation, "POEM" for
+	// part of a poem, or "" for none
+	Tag string
+
+	// The span of the word in Line.Text, in bytes, with an exclusive
+	// end
+	StartByte int
+	EndByte   int
+
+	// Its position in the line, counting from 1
+	Item int
+}
+
+// A line of a corpus file
+type Line struct {
+	// The path of the zip file, and the file in it
+	Zip  string
+	File string
+
+	// The line number, counting from 1
+	Number int
+
+	// The text of the line, without the word separators and tags
+	Text  string
+	Words []Word
+}
+
+// Where an item of the line is, in the form
+//
+//	data/best/news.zip(news/news_00056.txt) line 102 item 74
+func (s *Line) Ref(item int) string {
+	return fmt.Sprintf("%s(%s) line %d item %d", s.Zip, s.File, s.Number, item)
+}
+
+// The word which holds the byte at the offset in Text, or nil if there
+// is none
+func (s *Line) WordAt(offset int) *Word {
+	i := sort.Search(len(s.Words), func(i int) bool {
+		return s.Words[i].EndByte > offset
+	})
+	if i < len(s.Words) && s.Words[i].StartByte <= offset {
+		return &s.Words[i]
+	}
+	return nil
+}
+
+var bestTags = []string{"NE", "AB", "POEM"}
+
+// Split a line of the corpus into its words, and return the text
+// without the separators and tags.
+func ParseLine(raw string) (string, []Word) {
+	var sb strings.Builder
+	var words []Word
+	tag := ""
+	for _, item := range strings.Split(raw, "|") {
+		// Tags can surround one word or many
+		text := item
+		for t, rest, ok := cutTag(text, false); ok; t, rest, ok = cutTag(text, false) {
+			tag = t
+			text = rest
+		}
+		closed := false
+		for _, rest, ok := cutTag(text, true); ok; _, rest, ok = cutTag(text, true) {
+			text = rest
+			closed = true
+		}
+		if text != "" {
+			words = append(words, Word{
+				Text:      text,
+				Tag:       tag,
+				StartByte: sb.Len(),
+				EndByte:   sb.Len() + len(text),
+				Item:      len(words) + 1,
+			})
+			sb.WriteString(text)
+		}
+		if closed {
+			tag = ""
+		}
+	}
+	return sb.String(), words
+}
+
+// Cut an opening tag from the start of the text, or a closing tag from
+// its end
+func cutTag(text string, closing bool) (string, string, bool) {
+	for _, tag := range bestTags {
+		if closing && strings.HasSuffix(text, "</"+tag+">") {
+			return tag, strings.TrimSuffix(text, "</"+tag+">"), true
+		}
+		if !closing && strings.HasPrefix(text, "<"+tag+">") {
+			return tag, strings.TrimPrefix(text, "<"+tag+">"), true
+		}
+	}
+	return "", text, false
+}
+
+// Call the function for every line of every text file in the zip, in
+// the order of the file names. Files which are not UTF-8 are read as
+// TIS-620. If the function returns an error, reading stops and that
+// error is returned.
+func ReadZip(path string, fn func(*Line) error) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	files := make([]*zip.File, 0, len(zr.File))
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() && strings.EqualFold(filepath.Ext(f.Name), ".txt") {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	for _, f := range files {
+		if err := readZipFile(path, f, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readZipFile(path string, f *zip.File, fn func(*Line) error) error {
+	fh, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%s(%s): %w", path, f.Name, err)
+	}
+	defer fh.Close()
+	return readLines(path, f.Name, fh, fn)
+}
+
+func readLines(zipPath string, file string, r io.Reader, fn func(*Line) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		raw := strings.TrimSuffix(scanner.Text(), "\r")
+		if !utf8.ValidString(raw) {
+			raw = decodeTIS620(raw)
+		}
+		line := &Line{
+			Zip:    zipPath,
+			File:   file,
+			Number: number,
+		}
+		line.Text, line.Words = ParseLine(raw)
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s(%s): %w", zipPath, file, err)
+	}
+	return nil
+}
+
+// TIS-620 puts the Thai block of Unicode at 0xA1 and up
+func decodeTIS620(raw string) string {
+	var sb strings.Builder
+	for i := 0; i < len(raw); i++ {
+		b := raw[i]
+		if b >= 0xa1 && b <= 0xfb {
+			sb.WriteRune(0x0e01 + rune(b-0xa1))
+		} else if b < 0x80 {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteRune(utf8.RuneError)
+		}
+	}
+	return sb.String()
+}
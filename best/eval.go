@@ -0,0 +1,331 @@
+package best
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gilramir/paasaathai"
+)
+
+// Counts of boundaries that were found correctly, found wrongly, and
+// missed
+type Score struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// The fraction of the boundaries found that are right
+func (s Score) Precision() float64 {
+	if s.TruePositives+s.FalsePositives == 0 {
+		return 0
+	}
+	return float64(s.TruePositives) / float64(s.TruePositives+s.FalsePositives)
+}
+
+// The fraction of the right boundaries that were found
+func (s Score) Recall() float64 {
+	if s.TruePositives+s.FalseNegatives == 0 {
+		return 0
+	}
+	return float64(s.TruePositives) / float64(s.TruePositives+s.FalseNegatives)
+}
+
+func (s Score) F1() float64 {
+	p, r := s.Precision(), s.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+func (s Score) String() string {
+	return fmt.Sprintf("precision %.4f, recall %.4f, F1 %.4f (%d found, %d wrong, %d missed)",
+		s.Precision(), s.Recall(), s.F1(),
+		s.TruePositives, s.FalsePositives, s.FalseNegatives)
+}
+
+// Score the boundaries found against the right ones. Both are sorted
+// byte offsets.
+func (s *Score) add(found []int, expected []int) {
+	i, j := 0, 0
+	for i < len(found) || j < len(expected) {
+		switch {
+		case j == len(expected) || (i < len(found) && found[i] < expected[j]):
+			s.FalsePositives++
+			i++
+		case i == len(found) || expected[j] < found[i]:
+			s.FalseNegatives++
+			j++
+		default:
+			s.TruePositives++
+			i++
+			j++
+		}
+	}
+}
+
+// A cluster which the parser got wrong
+type Finding struct {
+	// Where it is, as in Line.Ref
+	Ref string `json:"ref"`
+
+	// The cluster, and the word of the corpus where it starts
+	Text string `json:"text"`
+	Word string `json:"word"`
+
+	// For an invalid cluster, the InvalidReason
+	Reason string `json:"reason,omitempty"`
+}
+
+func (s Finding) String() string {
+	if s.Reason != "" {
+		return fmt.Sprintf("%s in %s (word %s): %s", s.Text, s.Ref, s.Word, s.Reason)
+	}
+	return fmt.Sprintf("%s in %s (word %s)", s.Text, s.Ref, s.Word)
+}
+
+// The results of an evaluation. It can be saved as JSON, and compared
+// with a later one to find regressions.
+type Report struct {
+	Lines    int `json:"lines"`
+	Words    int `json:"words"`
+	Clusters int `json:"clusters"`
+
+	// A cluster boundary should be at every word boundary, so the
+	// recall should be 1. The precision is always lower, as most
+	// words have more than one cluster.
+	ClusterBoundaries Score `json:"cluster_boundaries"`
+
+	// Only set if the Evaluator has a WordSegmenter
+	WordBoundaries *Score `json:"word_boundaries,omitempty"`
+
+	// Clusters of Thai text which the parser rejected as invalid
+	Invalid []Finding `json:"invalid"`
+
+	// Clusters which cross a word boundary
+	Crossing []Finding `json:"crossing"`
+}
+
+// Runs the parsers on the corpus
+type Evaluator struct {
+	// If nil, NewGStackClusterParser is used
+	Parser *paasaathai.GStackClusterParser
+
+	// If set, the word boundaries are scored too
+	Segmenter *paasaathai.WordSegmenter
+
+	// If set, the clusters of every line are counted in it
+	Stats *paasaathai.RuleStats
+}
+
+// Evaluate every line of the zip files, in order
+func (s *Evaluator) EvaluateZips(paths ...string) (*Report, error) {
+	report := &Report{}
+	for _, path := range paths {
+		err := ReadZip(path, func(line *Line) error {
+			s.EvaluateLine(line, report)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// Evaluate one line, adding the results to the report
+func (s *Evaluator) EvaluateLine(line *Line, report *Report) {
+	gcp := s.Parser
+	if gcp == nil {
+		gcp = paasaathai.NewGStackClusterParser()
+	}
+	clusters := gcp.ParseGraphemeStacks(paasaathai.ParseGraphemeStacks(line.Text))
+
+	report.Lines++
+	report.Words += len(line.Words)
+	report.Clusters += len(clusters)
+
+	expected := wordBoundaries(line)
+
+	found := make([]int, 0, len(clusters))
+	for i := range clusters {
+		cc := &clusters[i]
+		if i > 0 {
+			found = append(found, cc.StartByte)
+		}
+		if cc.IsThai && !cc.IsValidThai {
+			report.Invalid = append(report.Invalid,
+				newFinding(line, cc, cc.InvalidReason.String()))
+		}
+		// The first word boundary after the start of the cluster
+		if j := sort.SearchInts(expected, cc.StartByte+1); j < len(expected) &&
+			expected[j] < cc.EndByte {
+			report.Crossing = append(report.Crossing, newFinding(line, cc, ""))
+		}
+	}
+	report.ClusterBoundaries.add(found, expected)
+
+	if s.Stats != nil {
+		s.Stats.Add(clusters, fmt.Sprintf("%s(%s) line %d", line.Zip, line.File, line.Number))
+	}
+
+	if s.Segmenter != nil {
+		if report.WordBoundaries == nil {
+			report.WordBoundaries = &Score{}
+		}
+		words := s.Segmenter.SegmentGStackClusters(clusters)
+		found = found[:0]
+		for i, w := range words {
+			if i > 0 && len(w.Clusters) > 0 {
+				found = append(found, w.Clusters[0].StartByte)
+			}
+		}
+		report.WordBoundaries.add(found, expected)
+	}
+}
+
+// The byte offsets in the line where words begin, except the first
+func wordBoundaries(line *Line) []int {
+	boundaries := make([]int, 0, len(line.Words))
+	for i, w := range line.Words {
+		if i > 0 {
+			boundaries = append(boundaries, w.StartByte)
+		}
+	}
+	return boundaries
+}
+
+func newFinding(line *Line, cc *paasaathai.GStackCluster, reason string) Finding {
+	f := Finding{
+		Text:   cc.Text,
+		Reason: reason,
+	}
+	if w := line.WordAt(cc.StartByte); w != nil {
+		f.Ref = line.Ref(w.Item)
+		f.Word = w.Text
+	} else {
+		f.Ref = line.Ref(0)
+	}
+	return f
+}
+
+// Write a summary of the report, followed by every finding
+func (s *Report) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d lines, %d words, %d clusters\n", s.Lines, s.Words, s.Clusters)
+	fmt.Fprintf(&sb, "Cluster boundaries: %s\n", s.ClusterBoundaries)
+	if s.WordBoundaries != nil {
+		fmt.Fprintf(&sb, "Word boundaries: %s\n", *s.WordBoundaries)
+	}
+	writeFindings(&sb, "invalid clusters", s.Invalid)
+	writeFindings(&sb, "clusters crossing word boundaries", s.Crossing)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeFindings(sb *strings.Builder, title string, findings []Finding) {
+	fmt.Fprintf(sb, "\n%d %s:\n", len(findings), title)
+	for _, f := range findings {
+		fmt.Fprintf(sb, "  %s\n", f)
+	}
+}
+
+// Save the report as JSON
+func (s *Report) Save(filename string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// Read a report saved with Save
+func LoadReport(filename string) (*Report, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return report, nil
+}
+
+// What changed between two reports
+type Comparison struct {
+	// Findings that are only in the new report, or only in the old one
+	NewInvalid    []Finding
+	FixedInvalid  []Finding
+	NewCrossing   []Finding
+	FixedCrossing []Finding
+
+	// Did a recall or precision get worse?
+	ScoresWorse bool
+}
+
+// Compare a report, after, with an earlier one, before, of the same
+// corpus
+func CompareReports(before *Report, after *Report) *Comparison {
+	c := &Comparison{}
+	c.NewInvalid, c.FixedInvalid = diffFindings(before.Invalid, after.Invalid)
+	c.NewCrossing, c.FixedCrossing = diffFindings(before.Crossing, after.Crossing)
+	c.ScoresWorse = scoreWorse(before.ClusterBoundaries, after.ClusterBoundaries)
+	if before.WordBoundaries != nil && after.WordBoundaries != nil {
+		c.ScoresWorse = c.ScoresWorse ||
+			scoreWorse(*before.WordBoundaries, *after.WordBoundaries)
+	}
+	return c
+}
+
+// Are there any regressions?
+func (s *Comparison) Regressed() bool {
+	return len(s.NewInvalid) > 0 || len(s.NewCrossing) > 0 || s.ScoresWorse
+}
+
+// Write the changes
+func (s *Comparison) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	if s.ScoresWorse {
+		fmt.Fprintf(&sb, "The precision or recall is worse\n")
+	}
+	writeFindings(&sb, "new invalid clusters", s.NewInvalid)
+	writeFindings(&sb, "invalid clusters fixed", s.FixedInvalid)
+	writeFindings(&sb, "new clusters crossing word boundaries", s.NewCrossing)
+	writeFindings(&sb, "clusters crossing word boundaries fixed", s.FixedCrossing)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func scoreWorse(before Score, after Score) bool {
+	return after.Precision() < before.Precision() || after.Recall() < before.Recall()
+}
+
+// The findings only in after, and the findings only in before
+func diffFindings(before []Finding, after []Finding) ([]Finding, []Finding) {
+	count := make(map[Finding]int, len(before))
+	for _, f := range before {
+		count[f]++
+	}
+	var added []Finding
+	for _, f := range after {
+		if count[f] > 0 {
+			count[f]--
+		} else {
+			added = append(added, f)
+		}
+	}
+	var removed []Finding
+	for _, f := range before {
+		if count[f] > 0 {
+			count[f]--
+			removed = append(removed, f)
+		}
+	}
+	return added, removed
+}
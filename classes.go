@@ -77,3 +77,34 @@ var LowClassRunes = NewSetFromSlice[rune]([]rune{
 	THAI_CHARACTER_LO_CHULA,
 	THAI_CHARACTER_HO_NOKHUK,
 })
+
+// Return the class of a consonant, or UndefinedClass if the
+// rune is not a consonant.
+func RuneConsonantClass(r rune) ConsonantClass {
+	switch {
+	case HighClassRunes.Has(r):
+		return HighClass
+	case MidClassRunes.Has(r):
+		return MidClass
+	case LowClassRunes.Has(r):
+		return LowClass
+	default:
+		return UndefinedClass
+	}
+}
+
+// Consonants which, in the final position, end a syllable with a
+// live (sonorant) sound: -ng, -n, -m, -y, or -w.
+// Every other final consonant ends the syllable with a dead (stop) sound.
+var LiveFinalConsonantRunes = NewSetFromSlice[rune]([]rune{
+	THAI_CHARACTER_NGO_NGU,
+	THAI_CHARACTER_YO_YING,
+	THAI_CHARACTER_NO_NEN,
+	THAI_CHARACTER_NO_NU,
+	THAI_CHARACTER_RO_RUA,
+	THAI_CHARACTER_LO_LING,
+	THAI_CHARACTER_LO_CHULA,
+	THAI_CHARACTER_MO_MA,
+	THAI_CHARACTER_YO_YAK,
+	THAI_CHARACTER_WO_WAEN,
+})
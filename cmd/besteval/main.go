@@ -0,0 +1,144 @@
+// Evaluate the parsers against the BEST corpus.
+//
+//	besteval [-dict words.txt] [-rules] [-save report.json] [-baseline old.json] [path ...]
+//
+// Each path is a zip file of the corpus, or a directory of them. The
+// default is data/best. It prints the precision and recall of the
+// cluster boundaries, and of the word boundaries if a dictionary is
+// given, and every cluster that was rejected as invalid or that crosses
+// a word boundary. With -rules, it also prints how many clusters each
+// rule made, and how many were invalid for each reason, with samples,
+// and the rules that never made a cluster.
+//
+// To track regressions, save a report with -save, and compare a later
+// run with it with -baseline. Then only the changes are printed, and
+// the exit code is 1 if anything got worse.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gilramir/paasaathai"
+	"github.com/gilramir/paasaathai/best"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Run the command line, and return the exit code
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("besteval", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dictFile := flags.String("dict", "", "a word list, to score the word boundaries too")
+	rules := flags.Bool("rules", false, "print the clusters made by each rule")
+	saveFile := flags.String("save", "", "save the report as JSON in this file")
+	baselineFile := flags.String("baseline", "", "compare with the report saved in this file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	fail := func(err error) int {
+		fmt.Fprintf(stderr, "besteval: %s\n", err)
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{filepath.Join("data", "best")}
+	}
+	zips, err := findZips(paths)
+	if err != nil {
+		return fail(err)
+	}
+
+	evaluator := &best.Evaluator{}
+	if *dictFile != "" {
+		dict, err := paasaathai.LoadDictionaryFile(*dictFile)
+		if err != nil {
+			return fail(err)
+		}
+		evaluator.Segmenter = &paasaathai.WordSegmenter{Dictionary: dict}
+	}
+	if *rules {
+		evaluator.Stats = paasaathai.NewRuleStats(nil)
+	}
+
+	// Load the baseline first, so that a bad file is found quickly
+	var baseline *best.Report
+	if *baselineFile != "" {
+		baseline, err = best.LoadReport(*baselineFile)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	report, err := evaluator.EvaluateZips(zips...)
+	if err != nil {
+		return fail(err)
+	}
+	if *saveFile != "" {
+		if err := report.Save(*saveFile); err != nil {
+			return fail(err)
+		}
+	}
+
+	code := 0
+	if baseline == nil {
+		if err := report.WriteText(stdout); err != nil {
+			return fail(err)
+		}
+	} else {
+		fmt.Fprintf(stdout, "Cluster boundaries: %s\n", report.ClusterBoundaries)
+		fmt.Fprintf(stdout, "          baseline: %s\n", baseline.ClusterBoundaries)
+		if report.WordBoundaries != nil && baseline.WordBoundaries != nil {
+			fmt.Fprintf(stdout, "Word boundaries: %s\n", *report.WordBoundaries)
+			fmt.Fprintf(stdout, "       baseline: %s\n", *baseline.WordBoundaries)
+		}
+		comparison := best.CompareReports(baseline, report)
+		if err := comparison.WriteText(stdout); err != nil {
+			return fail(err)
+		}
+		if comparison.Regressed() {
+			code = 1
+		}
+	}
+
+	if evaluator.Stats != nil {
+		fmt.Fprintln(stdout)
+		if err := evaluator.Stats.WriteText(stdout); err != nil {
+			return fail(err)
+		}
+	}
+	return code
+}
+
+// The zip files of the paths, which are zip files or directories of
+// them
+func findZips(paths []string) ([]string, error) {
+	var zips []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			zips = append(zips, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.zip"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s has no zip files", path)
+		}
+		sort.Strings(matches)
+		zips = append(zips, matches...)
+	}
+	return zips, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// Make a directory with a zip file of one corpus file
+func writeCorpus(c *C, text string) string {
+	dir := c.MkDir()
+	fh, err := os.Create(filepath.Join(dir, "news.zip"))
+	c.Assert(err, IsNil)
+	zw := zip.NewWriter(fh)
+	w, err := zw.Create("news/news_00001.txt")
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte(text))
+	c.Assert(err, IsNil)
+	c.Assert(zw.Close(), IsNil)
+	c.Assert(fh.Close(), IsNil)
+	return dir
+}
+
+func (s *MySuite) TestBaseline(c *C) {
+	saved := filepath.Join(c.MkDir(), "report.json")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-save", saved, writeCorpus(c, "กา| |มา|\n")}, &stdout, &stderr)
+	c.Assert(code, Equals, 0, Commentf("%s", stderr.String()))
+	c.Check(stdout.String(), Matches, "(?s)1 lines, 3 words, 3 clusters\n"+
+		"Cluster boundaries: precision 1.0000, recall 1.0000.*")
+
+	// The same corpus has no regressions
+	stdout.Reset()
+	code = run([]string{"-baseline", saved, writeCorpus(c, "กา| |มา|\n")}, &stdout, &stderr)
+	c.Check(code, Equals, 0)
+
+	// But one where a word boundary is inside a cluster does
+	stdout.Reset()
+	code = run([]string{"-baseline", saved, writeCorpus(c, "กา| |มา|\nเก|า|\n")}, &stdout, &stderr)
+	c.Check(code, Equals, 1)
+	c.Check(stdout.String(), Matches,
+		"(?s).*1 new clusters crossing word boundaries:\n  เกา in .*news.zip\\(news/news_00001.txt\\) line 2 item 1.*")
+}
+
+func (s *MySuite) TestErrors(c *C) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{c.MkDir()}, &stdout, &stderr)
+	c.Check(code, Equals, 2)
+	c.Check(stderr.String(), Matches, "besteval: .* has no zip files\n")
+}
+
+func (s *MySuite) TestRules(c *C) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-rules", writeCorpus(c, "กา| |มา|\n")}, &stdout, &stderr)
+	c.Assert(code, Equals, 0, Commentf("%s", stderr.String()))
+	c.Check(stdout.String(), Matches, "(?s).*\n2 Thai clusters\n\n"+
+		"Clusters made by each rule:\n"+
+		"  sara_a_aa: 2 \\(100.00%\\)\n"+
+		"    \\[กา\\] มา in .*news.zip\\(news/news_00001.txt\\) line 1\n"+
+		"    กา \\[มา\\] in .*\n"+
+		".*rules never made a cluster:\n.*")
+}
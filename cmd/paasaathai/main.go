@@ -0,0 +1,397 @@
+// Inspect and segment Thai text from the command line.
+//
+//	paasaathai <command> [-format text|json|tsv] [-dict words.txt] [-rules rules.txt] [file ...]
+//
+// The text is read from the files, or from stdin if there are none, and
+// is handled one line at a time. The commands are:
+//
+//	stacks     print the GraphemeStacks of each line
+//	clusters   print the GStackClusters of each line
+//	names      print the names of the code points of each line
+//	validate   print the problems in each line, and exit with 1 if
+//	           there are any errors
+//	explain    explain which rules split each line into clusters
+//	segment    split each line into the words of the -dict word list
+//
+// With -format json, each item is printed as a JSON object on a line of
+// its own. With -format tsv, a header is printed first, and then one
+// row per item.
+//
+// With -rules, the rules of a rule file are added to the cluster
+// parser's rules, so that new rules can be tried without building
+// anything.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gilramir/paasaathai"
+)
+
+// Where a line came from
+type location struct {
+	file string
+	line int
+}
+
+// The parsers that the commands use, as the flags set them up
+type parsers struct {
+	clusters  *paasaathai.GStackClusterParser
+	segmenter *paasaathai.WordSegmenter
+}
+
+// Handles one line of input, printing what it finds. Returns false if
+// the line has an error.
+type commandFunc func(p *printer, ps *parsers, loc location, text string) bool
+
+type command struct {
+	summary string
+	run     commandFunc
+}
+
+var commands = map[string]command{
+	"stacks":   {"print the GraphemeStacks of each line", runStacks},
+	"clusters": {"print the GStackClusters of each line", runClusters},
+	"names":    {"print the names of the code points of each line", runNames},
+	"validate": {"print the problems in each line, and exit with 1 if there are any errors", runValidate},
+	"explain":  {"explain which rules split each line into clusters", runExplain},
+	"segment":  {"split each line into the words of the -dict word list", runSegment},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Run the command line, and return the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	name := args[0]
+	cmd, has := commands[name]
+	if !has {
+		if name != "-h" && name != "-help" && name != "--help" {
+			fmt.Fprintf(stderr, "paasaathai: unknown command %q\n", name)
+		}
+		usage(stderr)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("paasaathai "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "the output format: text, json, or tsv")
+	dictFile := flags.String("dict", "", "the word list for segment, with one word per line")
+	rulesFile := flags.String("rules", "", "add the rules of this rule file to the cluster parser")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" && *format != "tsv" {
+		fmt.Fprintf(stderr, "paasaathai: unknown format %q\n", *format)
+		return 2
+	}
+
+	ps, err := newParsers(name, *dictFile, *rulesFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "paasaathai: %s\n", err)
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	p := &printer{
+		format: *format,
+		w:      out,
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	ok := true
+	for _, file := range files {
+		fileOk, err := runFile(cmd.run, p, ps, file, stdin)
+		if err != nil {
+			out.Flush()
+			fmt.Fprintf(stderr, "paasaathai: %s\n", err)
+			return 2
+		}
+		ok = ok && fileOk
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: paasaathai <command> [-format text|json|tsv] [-dict words.txt] [-rules rules.txt] [file ...]\n\n")
+	fmt.Fprintf(w, "Reads stdin if no files are given. The commands are:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// Make the parsers for the command, from the -dict and -rules files
+func newParsers(name string, dictFile string, rulesFile string) (*parsers, error) {
+	ps := &parsers{clusters: paasaathai.NewGStackClusterParser()}
+	if rulesFile != "" {
+		gcp := &paasaathai.GStackClusterParser{}
+		err := gcp.AddRuleFile(rulesFile)
+		if err == nil {
+			err = gcp.Initialize()
+		}
+		if err != nil {
+			return nil, err
+		}
+		ps.clusters = gcp
+	}
+
+	if dictFile == "" {
+		if name == "segment" {
+			return nil, fmt.Errorf("segment needs a word list, given with -dict")
+		}
+		return ps, nil
+	}
+	dict, err := paasaathai.LoadDictionaryFile(dictFile)
+	if err != nil {
+		return nil, err
+	}
+	ps.segmenter = &paasaathai.WordSegmenter{Dictionary: dict}
+	return ps, nil
+}
+
+// Run the command on each line of the file; "-" is stdin
+func runFile(run commandFunc, p *printer, ps *parsers, file string, stdin io.Reader) (bool, error) {
+	r := stdin
+	if file != "-" {
+		fh, err := os.Open(file)
+		if err != nil {
+			return false, err
+		}
+		defer fh.Close()
+		r = fh
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	ok := true
+	loc := location{file: file}
+	for scanner.Scan() {
+		loc.line++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if !run(p, ps, loc, text) {
+			ok = false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("%s: %w", file, err)
+	}
+	return ok, nil
+}
+
+func runStacks(p *printer, ps *parsers, loc location, text string) bool {
+	for _, gs := range paasaathai.ParseGraphemeStacks(text) {
+		p.print(gs.Repr(), record{
+			{"file", loc.file},
+			{"line", loc.line},
+			{"start_byte", gs.StartByte},
+			{"end_byte", gs.EndByte},
+			{"text", gs.Text},
+			{"main", runeName(gs.Main)},
+			{"diacritic_vowel", runeName(gs.DiacriticVowel)},
+			{"upper_diacritic", runeName(gs.UpperDiacritic)},
+		})
+	}
+	return true
+}
+
+func runClusters(p *printer, ps *parsers, loc location, text string) bool {
+	clusters := ps.clusters.ParseGraphemeStacks(paasaathai.ParseGraphemeStacks(text))
+	for i := range clusters {
+		cc := &clusters[i]
+		reason := ""
+		if cc.IsThai && !cc.IsValidThai {
+			reason = cc.InvalidReason.String()
+		}
+		p.print(cc.Repr(), record{
+			{"file", loc.file},
+			{"line", loc.line},
+			{"start_byte", cc.StartByte},
+			{"end_byte", cc.EndByte},
+			{"text", cc.Text},
+			{"thai", cc.IsThai},
+			{"valid", cc.IsValidThai},
+			{"reason", reason},
+			{"rule", cc.MatchingRule},
+		})
+	}
+	return true
+}
+
+func runNames(p *printer, ps *parsers, loc location, text string) bool {
+	p.print(paasaathai.StringToRuneNames(text), record{
+		{"file", loc.file},
+		{"line", loc.line},
+		{"text", text},
+		{"names", paasaathai.StringToRuneNames(text)},
+	})
+	return true
+}
+
+func runValidate(p *printer, ps *parsers, loc location, text string) bool {
+	clusters := ps.clusters.ParseGraphemeStacks(paasaathai.ParseGraphemeStacks(text))
+	ok := true
+	for _, d := range paasaathai.ValidateGStackClusters(clusters) {
+		if d.Severity == paasaathai.SeverityError {
+			ok = false
+		}
+		p.print(fmt.Sprintf("%s:%d:%s", loc.file, loc.line, d), record{
+			{"file", loc.file},
+			{"line", loc.line},
+			{"start_byte", d.StartByte},
+			{"end_byte", d.EndByte},
+			{"text", text[d.StartByte:d.EndByte]},
+			{"severity", d.Severity.String()},
+			{"reason", d.Reason.String()},
+			{"message", d.Message},
+		})
+	}
+	return ok
+}
+
+// In the text format, the explanation of each line; otherwise, every
+// rule that was tried
+func runExplain(p *printer, ps *parsers, loc location, text string) bool {
+	if p.format == "text" {
+		p.print(strings.TrimSuffix(ps.clusters.Explain(text), "\n"), nil)
+		return true
+	}
+	traced := ps.clusters.WithTracer(func(input []paasaathai.GraphemeStack, e *paasaathai.TraceEvent) {
+		var sb strings.Builder
+		if e.PatternMatched {
+			for _, gs := range input[e.Range.Start:e.Range.End] {
+				sb.WriteString(gs.Text)
+			}
+		}
+		p.print("", record{
+			{"file", loc.file},
+			{"line", loc.line},
+			{"position", e.Position},
+			{"rule", e.Rule},
+			{"pattern_matched", e.PatternMatched},
+			{"matched", e.Matched},
+			{"text", sb.String()},
+		})
+	})
+	traced.ParseGraphemeStacks(paasaathai.ParseGraphemeStacks(text))
+	return true
+}
+
+// In the text format, the words of each line, separated by "|";
+// otherwise, each word
+func runSegment(p *printer, ps *parsers, loc location, text string) bool {
+	clusters := ps.clusters.ParseGraphemeStacks(paasaathai.ParseGraphemeStacks(text))
+	words := ps.segmenter.SegmentGStackClusters(clusters)
+	if p.format == "text" {
+		texts := make([]string, len(words))
+		for i := range words {
+			texts[i] = words[i].Text
+		}
+		p.print(strings.Join(texts, "|"), nil)
+		return true
+	}
+	for i := range words {
+		w := &words[i]
+		p.print("", record{
+			{"file", loc.file},
+			{"line", loc.line},
+			{"start_byte", w.Clusters[0].StartByte},
+			{"end_byte", w.Clusters[len(w.Clusters)-1].EndByte},
+			{"text", w.Text},
+			{"in_dictionary", w.InDictionary},
+		})
+	}
+	return true
+}
+
+func runeName(r rune) string {
+	if r == 0 {
+		return ""
+	}
+	if name := paasaathai.RuneToName(r); name != "" {
+		return name
+	}
+	return string(r)
+}
+
+// A JSON object or TSV row, with its fields in order
+type record []field
+
+type field struct {
+	name  string
+	value interface{}
+}
+
+// Prints each item in the output format
+type printer struct {
+	format string
+	w      io.Writer
+
+	// Has the TSV header been printed?
+	printedHeader bool
+}
+
+// Print an item; text is what is printed in the text format
+func (s *printer) print(text string, rec record) {
+	switch s.format {
+	case "json":
+		var sb strings.Builder
+		sb.WriteString("{")
+		for i, f := range rec {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			name, _ := json.Marshal(f.name)
+			value, _ := json.Marshal(f.value)
+			sb.Write(name)
+			sb.WriteString(":")
+			sb.Write(value)
+		}
+		sb.WriteString("}")
+		fmt.Fprintln(s.w, sb.String())
+
+	case "tsv":
+		if !s.printedHeader {
+			names := make([]string, len(rec))
+			for i, f := range rec {
+				names[i] = f.name
+			}
+			fmt.Fprintln(s.w, strings.Join(names, "\t"))
+			s.printedHeader = true
+		}
+		values := make([]string, len(rec))
+		for i, f := range rec {
+			values[i] = tsvEscaper.Replace(fmt.Sprint(f.value))
+		}
+		fmt.Fprintln(s.w, strings.Join(values, "\t"))
+
+	default:
+		fmt.Fprintln(s.w, text)
+	}
+}
+
+// Tabs and newlines can't be in a TSV field
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// Run the command line with the input, returning the exit code, stdout,
+// and stderr
+func runWith(args []string, input string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(input), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func (s *MySuite) TestStacks(c *C) {
+	code, stdout, _ := runWith([]string{"stacks"}, "กี่\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals, "<GraphemeStack กี่ MAIN=THAI_CHARACTER_KO_KAI "+
+		"DV=THAI_CHARACTER_SARA_II UD=THAI_CHARACTER_MAI_EK>\n")
+}
+
+func (s *MySuite) TestClustersTSV(c *C) {
+	code, stdout, _ := runWith([]string{"clusters", "-format", "tsv"}, "กา a\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals,
+		"file\tline\tstart_byte\tend_byte\ttext\tthai\tvalid\treason\trule\n"+
+			"-\t1\t0\t6\tกา\ttrue\ttrue\t\tsara_a_aa\n"+
+			"-\t1\t6\t7\t \tfalse\tfalse\t\t\n"+
+			"-\t1\t7\t8\ta\tfalse\tfalse\t\t\n")
+}
+
+func (s *MySuite) TestNamesJSON(c *C) {
+	code, stdout, _ := runWith([]string{"names", "-format", "json"}, "กa\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals,
+		`{"file":"-","line":1,"text":"กa","names":"THAI_CHARACTER_KO_KAI, a"}`+"\n")
+}
+
+func (s *MySuite) TestValidate(c *C) {
+	code, stdout, _ := runWith([]string{"validate"}, "กา\nข ุ\n")
+	c.Check(code, Equals, 1)
+	c.Check(stdout, Equals, "-:2:4-7: error: SoloDiacritic: "+
+		"An upper/lower diacritic vowel should be connected to a consonant\n")
+
+	code, stdout, _ = runWith([]string{"validate"}, "กา\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals, "")
+}
+
+func (s *MySuite) TestFiles(c *C) {
+	dir := c.MkDir()
+	file := filepath.Join(dir, "input.txt")
+	c.Assert(os.WriteFile(file, []byte("ก\r\nข\r\n"), 0644), IsNil)
+
+	code, stdout, _ := runWith([]string{"names", "-format", "tsv", file}, "")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals, "file\tline\ttext\tnames\n"+
+		file+"\t1\tก\tTHAI_CHARACTER_KO_KAI\n"+
+		file+"\t2\tข\tTHAI_CHARACTER_KHO_KHAI\n")
+
+	code, _, stderr := runWith([]string{"names", filepath.Join(dir, "missing")}, "")
+	c.Check(code, Equals, 2)
+	c.Check(stderr, Matches, "paasaathai: open .*missing: no such file or directory\n")
+}
+
+func (s *MySuite) TestUsage(c *C) {
+	code, _, stderr := runWith(nil, "")
+	c.Check(code, Equals, 2)
+	c.Check(stderr, Matches, "(?s)Usage: paasaathai.*validate.*")
+
+	code, _, stderr = runWith([]string{"romanize"}, "")
+	c.Check(code, Equals, 2)
+	c.Check(stderr, Matches, "(?s)paasaathai: unknown command \"romanize\".*")
+
+	code, _, _ = runWith([]string{"stacks", "-format", "xml"}, "")
+	c.Check(code, Equals, 2)
+}
+
+func (s *MySuite) TestSegment(c *C) {
+	dict := filepath.Join(c.MkDir(), "words.txt")
+	c.Assert(os.WriteFile(dict, []byte("ฉัน\nกิน\nข้าว\n"), 0644), IsNil)
+
+	code, stdout, _ := runWith([]string{"segment", "-dict", dict}, "ฉันกินข้าว\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals, "ฉัน|กิน|ข้าว\n")
+
+	code, stdout, _ = runWith([]string{"segment", "-dict", dict, "-format", "tsv"}, "กินa\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals,
+		"file\tline\tstart_byte\tend_byte\ttext\tin_dictionary\n"+
+			"-\t1\t0\t9\tกิน\ttrue\n"+
+			"-\t1\t9\t10\ta\tfalse\n")
+
+	code, _, stderr := runWith([]string{"segment"}, "")
+	c.Check(code, Equals, 2)
+	c.Check(stderr, Equals, "paasaathai: segment needs a word list, given with -dict\n")
+}
+
+func (s *MySuite) TestExplain(c *C) {
+	code, stdout, _ := runWith([]string{"explain"}, "ก\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Matches, "(?s)ก: 1 stacks, 1 clusters\n\nก, at stack 0: rule consonant\n.*")
+
+	code, stdout, _ = runWith([]string{"explain", "-format", "json"}, "ก\n")
+	c.Check(code, Equals, 0)
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	c.Check(lines[len(lines)-1], Equals, `{"file":"-","line":1,"position":0,`+
+		`"rule":"consonant","pattern_matched":true,"matched":true,"text":"ก"}`)
+}
+
+func (s *MySuite) TestRules(c *C) {
+	dir := c.MkDir()
+	file := filepath.Join(dir, "rules.txt")
+	c.Assert(os.WriteFile(file, []byte("rule lone_consonant\n"+
+		"\tpattern ([:consonant:])\n\tfirst_consonant 1\n\tbefore consonant\n"), 0644), IsNil)
+
+	code, stdout, _ := runWith([]string{"clusters", "-format", "tsv", "-rules", file}, "ก\n")
+	c.Check(code, Equals, 0)
+	c.Check(stdout, Equals,
+		"file\tline\tstart_byte\tend_byte\ttext\tthai\tvalid\treason\trule\n"+
+			"-\t1\t0\t3\tก\ttrue\ttrue\t\tlone_consonant\n")
+
+	code, _, stderr := runWith([]string{"clusters", "-rules", filepath.Join(dir, "missing")}, "ก\n")
+	c.Check(code, Equals, 2)
+	c.Check(stderr, Matches, "paasaathai: open .*missing: no such file or directory\n")
+}
@@ -0,0 +1,194 @@
+package paasaathai
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Sorts text in the order of the Royal Institute dictionary.
+//
+// Words are ordered by their consonants and vowels first. A front
+// vowel is stored before its consonant, but the dictionary sorts it
+// after the consonant, so เกา sorts as if it were กเา: after กา, and
+// under ก. Tone marks, mai taikhu, and thanthakhat only matter when
+// two words are otherwise the same; then the word without the mark
+// comes first, followed by mai ek, mai tho, mai tri, and mai chattawa.
+//
+// Code points that are not Thai sort by their code point, so Latin
+// sorts before Thai. Strings which are equal except for their
+// normalization are ordered by their UTF-8.
+//
+// A Collator can be shared by many goroutines.
+type Collator struct {
+	parser *GStackClusterParser
+}
+
+func NewCollator() *Collator {
+	return &Collator{
+		parser: NewGStackClusterParser(),
+	}
+}
+
+// Returns a key for the text; comparing the keys with bytes.Compare
+// is the same as comparing the texts with Compare. It is faster to
+// make the keys once when sorting many strings.
+func (s *Collator) Key(text string) []byte {
+	return s.AppendKey(nil, text)
+}
+
+// Append the key for the text to dst
+func (s *Collator) AppendKey(dst []byte, text string) []byte {
+	clusters := s.parser.ParseGraphemeStacks(ParseGraphemeStacks(text))
+
+	// The stacks, with each front vowel moved after its consonant
+	gstacks := make([]GraphemeStack, 0, len(clusters)*2)
+	for i := range clusters {
+		cc := &clusters[i]
+		if cc.FrontVowel.Main != 0 && cc.FirstConsonant.Main != 0 {
+			gstacks = append(gstacks, cc.FirstConsonant, cc.FrontVowel)
+			gstacks = append(gstacks, cc.Tail...)
+		} else {
+			gstacks = append(gstacks, cc.stacks()...)
+		}
+	}
+
+	// Primary weights: every code point but the marks, as three bytes
+	for _, gs := range gstacks {
+		for _, r := range []rune{gs.Main, gs.DiacriticVowel, gs.UpperDiacritic} {
+			if r != 0 && collationMarkWeight(r) == 0 {
+				w := collationPrimaryWeight(r)
+				dst = append(dst, byte(w>>16), byte(w>>8), byte(w))
+			}
+		}
+	}
+	dst = append(dst, 0, 0, 0)
+
+	// Secondary weights: the mark on each stack
+	for _, gs := range gstacks {
+		w := byte(1)
+		for _, r := range []rune{gs.Main, gs.DiacriticVowel, gs.UpperDiacritic} {
+			if mw := collationMarkWeight(r); mw != 0 {
+				w = mw
+			}
+		}
+		dst = append(dst, w)
+	}
+	dst = append(dst, 0)
+
+	// Last, the text itself
+	return append(dst, text...)
+}
+
+// Returns -1 if a sorts before b, 1 if a sorts after b, and 0 if they
+// are the same.
+func (s *Collator) Compare(a, b string) int {
+	return bytes.Compare(s.Key(a), s.Key(b))
+}
+
+func (s *Collator) Less(a, b string) bool {
+	return s.Compare(a, b) < 0
+}
+
+// Sort the texts in place. Equal texts keep their order.
+func (s *Collator) Sort(texts []string) {
+	keys := make([][]byte, len(texts))
+	for i, text := range texts {
+		keys[i] = s.Key(text)
+	}
+	sort.Stable(collationSorter{texts, keys})
+}
+
+type collationSorter struct {
+	texts []string
+	keys  [][]byte
+}
+
+func (s collationSorter) Len() int {
+	return len(s.texts)
+}
+
+func (s collationSorter) Less(i, j int) bool {
+	return bytes.Compare(s.keys[i], s.keys[j]) < 0
+}
+
+func (s collationSorter) Swap(i, j int) {
+	s.texts[i], s.texts[j] = s.texts[j], s.texts[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// Does a sort before b, in the order of the Royal Institute dictionary?
+func Less(a, b string) bool {
+	return NewCollator().Less(a, b)
+}
+
+// The sort key of the text, in the order of the Royal Institute
+// dictionary. See Collator.Key.
+func SortKey(text string) []byte {
+	return NewCollator().Key(text)
+}
+
+// The Thai block in dictionary order: the consonants, the vowels, and
+// then everything else in code point order. The consonants and vowels
+// are already in dictionary order in Unicode, with ฤ after ร and ฦ
+// after ล.
+var thaiCollationOrder = func() []rune {
+	order := make([]rune, 0, 0x80)
+	for r := THAI_CHARACTER_KO_KAI; r <= THAI_CHARACTER_HO_NOKHUK; r++ {
+		order = append(order, r)
+	}
+	for r := THAI_CHARACTER_SARA_A; r <= THAI_CHARACTER_SARA_UU; r++ {
+		order = append(order, r)
+	}
+	for r := THAI_CHARACTER_SARA_E; r <= THAI_CHARACTER_LAKKHANGYAO; r++ {
+		order = append(order, r)
+	}
+	placed := NewSetFromSlice(order)
+	for r := rune(0x0e00); r < 0x0e80; r++ {
+		if !placed.Has(r) {
+			order = append(order, r)
+		}
+	}
+	return order
+}()
+
+// The position of each code point of the Thai block in
+// thaiCollationOrder
+var thaiCollationRank = func() map[rune]int32 {
+	rank := make(map[rune]int32, len(thaiCollationOrder))
+	for i, r := range thaiCollationOrder {
+		rank[r] = int32(i)
+	}
+	return rank
+}()
+
+// The primary weight of a code point. The Thai block is reordered in
+// place; other code points keep their order. It is never 0.
+func collationPrimaryWeight(r rune) int32 {
+	if rank, has := thaiCollationRank[r]; has {
+		r = 0x0e00 + rank
+	}
+	return r + 1
+}
+
+// The secondary weight of a mark, or 0 if the code point is not one.
+// A stack without a mark has a weight of 1.
+func collationMarkWeight(r rune) byte {
+	switch r {
+	case THAI_CHARACTER_MAITAIKHU:
+		return 2
+	case THAI_CHARACTER_MAI_EK:
+		return 3
+	case THAI_CHARACTER_MAI_THO:
+		return 4
+	case THAI_CHARACTER_MAI_TRI:
+		return 5
+	case THAI_CHARACTER_MAI_CHATTAWA:
+		return 6
+	case THAI_CHARACTER_THANTHAKHAT:
+		return 7
+	case THAI_CHARACTER_YAMAKKAN:
+		return 8
+	default:
+		return 0
+	}
+}
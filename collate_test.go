@@ -0,0 +1,78 @@
+package paasaathai
+
+import (
+	"math/rand"
+	"sort"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestCollatorOrder(c *C) {
+	// In the order of the Royal Institute dictionary
+	expected := []string{
+		"apple",
+		"zoo",
+		"กง",
+		"กา",
+		"ก่า",
+		"ก้า",
+		"กิน",
+		"กุ้ง",
+		"เก",
+		"เกลือ",
+		"เกา",
+		"แก",
+		"โกง",
+		"ใกล้",
+		"ไก่",
+		"ขา",
+		"รัก",
+		"ฤดู",
+		"ลม",
+		"ฦๅ",
+		"วัน",
+		"อ่าน",
+		"ฮา",
+	}
+
+	texts := make([]string, len(expected))
+	copy(texts, expected)
+	r := rand.New(rand.NewSource(1))
+	r.Shuffle(len(texts), func(i, j int) {
+		texts[i], texts[j] = texts[j], texts[i]
+	})
+
+	NewCollator().Sort(texts)
+	c.Check(texts, DeepEquals, expected)
+}
+
+func (s *MySuite) TestCollatorToneMarksAreSecondary(c *C) {
+	collator := NewCollator()
+	// The tone mark only matters when the rest is the same
+	c.Check(collator.Less("ก่า", "กาก"), Equals, true)
+	c.Check(collator.Less("กา", "ก่า"), Equals, true)
+	c.Check(collator.Less("เด็ก", "เดก"), Equals, false)
+	c.Check(collator.Less("เดก", "เด็ก"), Equals, true)
+	c.Check(collator.Compare("ไก่", "ไก่"), Equals, 0)
+}
+
+func (s *MySuite) TestCollatorNormalization(c *C) {
+	// THAI_CHARACTER_KO_KAI, THAI_CHARACTER_MAI_EK, THAI_CHARACTER_SARA_U
+	// has the same consonants and vowels as กุ่
+	c.Check(Less("\u0e01\u0e48\u0e38", "กุก"), Equals, true)
+	c.Check(Less("กา", "\u0e01\u0e48\u0e38"), Equals, true)
+	// Only the UTF-8 tells them apart
+	c.Check(Less("\u0e01\u0e38\u0e48", "\u0e01\u0e48\u0e38"), Equals, true)
+}
+
+func (s *MySuite) TestSortKey(c *C) {
+	texts := []string{"ไก่", "เกา", "กา", "a", "ขา", "ก้า"}
+	sorted := make([]string, len(texts))
+	copy(sorted, texts)
+	NewCollator().Sort(sorted)
+
+	sort.Slice(texts, func(i, j int) bool {
+		return string(SortKey(texts[i])) < string(SortKey(texts[j]))
+	})
+	c.Check(texts, DeepEquals, sorted)
+}
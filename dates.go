@@ -0,0 +1,250 @@
+package paasaathai
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The Buddhist Era (พ.ศ.) year is the Gregorian year plus this
+const BuddhistEraOffset = 543
+
+func GregorianToBuddhistYear(year int) int {
+	return year + BuddhistEraOffset
+}
+
+func BuddhistToGregorianYear(year int) int {
+	return year - BuddhistEraOffset
+}
+
+// The names of the months, from January, at index 0
+var ThaiMonthNames = [12]string{
+	"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน",
+	"กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม",
+}
+
+var ThaiMonthAbbreviations = [12]string{
+	"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.",
+	"ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค.",
+}
+
+// The names of the days of the week, indexed by time.Weekday
+var ThaiWeekdayNames = [7]string{
+	"อาทิตย์", "จันทร์", "อังคาร", "พุธ", "พฤหัสบดี", "ศุกร์", "เสาร์",
+}
+
+var ThaiWeekdayAbbreviations = [7]string{
+	"อา.", "จ.", "อ.", "พ.", "พฤ.", "ศ.", "ส.",
+}
+
+const (
+	thaiBuddhistEra  = "พ.ศ."
+	thaiCommonEra    = "ค.ศ."
+	thaiWeekdayStart = "วัน"
+	thaiDayOf        = "ที่"
+	thaiHours        = "น."
+)
+
+// How FormatThaiDate writes a date. The zero value writes dates like
+// 18 ตุลาคม 2569.
+type ThaiDateFormat struct {
+	// Start with the day of the week, as in วันอาทิตย์ที่ 18 ตุลาคม 2569
+	Weekday bool
+
+	// Use the abbreviated month name, as in 18 ต.ค. 2569
+	AbbreviatedMonth bool
+
+	// Only write the last two digits of the year, as in 18 ต.ค. 69
+	ShortYear bool
+
+	// Write พ.ศ. before the year, as in 18 ตุลาคม พ.ศ. 2569
+	Era bool
+
+	// End with the time, as in 18 ตุลาคม 2569 10:45 น.
+	Time bool
+
+	// Write the numbers with Thai digits
+	ThaiDigits bool
+}
+
+// Write the date with the Thai month name and the Buddhist Era year
+func (s ThaiDateFormat) Format(t time.Time) string {
+	var parts []string
+	if s.Weekday {
+		parts = append(parts, thaiWeekdayStart+ThaiWeekdayNames[t.Weekday()]+thaiDayOf)
+	}
+	parts = append(parts, strconv.Itoa(t.Day()))
+
+	if s.AbbreviatedMonth {
+		parts = append(parts, ThaiMonthAbbreviations[t.Month()-1])
+	} else {
+		parts = append(parts, ThaiMonthNames[t.Month()-1])
+	}
+
+	if s.Era {
+		parts = append(parts, thaiBuddhistEra)
+	}
+	year := GregorianToBuddhistYear(t.Year())
+	if s.ShortYear {
+		parts = append(parts, fmt.Sprintf("%02d", year%100))
+	} else {
+		parts = append(parts, strconv.Itoa(year))
+	}
+
+	if s.Time {
+		parts = append(parts, fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute()),
+			thaiHours)
+	}
+
+	text := strings.Join(parts, " ")
+	if s.ThaiDigits {
+		text = ArabicToThaiDigits(text)
+	}
+	return text
+}
+
+// Write a date like 18 ตุลาคม 2569
+func FormatThaiDate(t time.Time) string {
+	return ThaiDateFormat{}.Format(t)
+}
+
+// Read a date written in Thai, in the location, or in time.Local if
+// it is nil. These forms, and others like them, are understood:
+//
+//	18 ตุลาคม 2569
+//	18 ต.ค. 69
+//	วันอาทิตย์ที่ 18 ตุลาคม พ.ศ. 2569
+//	๑๘ ต.ค. ๒๕๖๙ 10:45 น.
+//
+// The year is in the Buddhist Era unless it follows ค.ศ. A two-digit
+// year is in the 2500s of the Buddhist Era. If the day of the week is
+// given, it must be right.
+func ParseThaiDate(text string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	fail := func(format string, args ...interface{}) (time.Time, error) {
+		return time.Time{}, fmt.Errorf("%q is not a Thai date: %s", text,
+			fmt.Sprintf(format, args...))
+	}
+
+	fields := strings.Fields(ThaiToArabicDigits(text))
+	next := func() string {
+		if len(fields) == 0 {
+			return ""
+		}
+		field := fields[0]
+		fields = fields[1:]
+		return field
+	}
+
+	// The day, which may follow the day of the week
+	weekday := -1
+	field := next()
+	if strings.HasPrefix(field, thaiWeekdayStart) {
+		name := strings.TrimPrefix(field, thaiWeekdayStart)
+		name, field, _ = strings.Cut(name, thaiDayOf)
+		weekday = indexOfName(ThaiWeekdayNames[:], name)
+		if weekday == -1 {
+			return fail("unknown day of the week %s", name)
+		}
+		if field == "" {
+			field = next()
+		}
+	} else if i := indexOfName(ThaiWeekdayAbbreviations[:], field); i != -1 {
+		weekday = i
+		field = next()
+	}
+	if field == thaiDayOf {
+		field = next()
+	}
+	day, err := strconv.Atoi(field)
+	if err != nil {
+		return fail("bad day %q", field)
+	}
+
+	field = next()
+	month := indexOfName(ThaiMonthNames[:], field)
+	if month == -1 {
+		month = indexOfAbbreviation(ThaiMonthAbbreviations[:], field)
+	}
+	if month == -1 {
+		return fail("unknown month %q", field)
+	}
+
+	field = next()
+	buddhistEra := true
+	switch field {
+	case thaiBuddhistEra, strings.TrimSuffix(thaiBuddhistEra, "."):
+		field = next()
+	case thaiCommonEra, strings.TrimSuffix(thaiCommonEra, "."):
+		buddhistEra = false
+		field = next()
+	}
+	year, err := strconv.Atoi(field)
+	if err != nil || year < 0 {
+		return fail("bad year %q", field)
+	}
+	if len(field) <= 2 {
+		if buddhistEra {
+			year += 2500
+		} else {
+			year += 2000
+		}
+	}
+	if buddhistEra {
+		year = BuddhistToGregorianYear(year)
+	}
+
+	var hour, minute int
+	if field = next(); field != "" {
+		hours, minutes, found := strings.Cut(field, ":")
+		if !found {
+			hours, minutes, found = strings.Cut(field, ".")
+		}
+		hour, err = strconv.Atoi(hours)
+		if err == nil {
+			minute, err = strconv.Atoi(minutes)
+		}
+		if !found || err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+			return fail("bad time %q", field)
+		}
+		if field = next(); field == thaiHours {
+			field = next()
+		}
+	}
+	if field != "" {
+		return fail("unexpected %q", field)
+	}
+
+	t := time.Date(year, time.Month(month+1), day, hour, minute, 0, 0, loc)
+	if t.Day() != day {
+		return fail("there is no day %d in %s", day, ThaiMonthNames[month])
+	}
+	if weekday != -1 && time.Weekday(weekday) != t.Weekday() {
+		return fail("it is a %s, not a %s", ThaiWeekdayNames[t.Weekday()],
+			ThaiWeekdayNames[weekday])
+	}
+	return t, nil
+}
+
+func indexOfName(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Abbreviations match with or without their periods
+func indexOfAbbreviation(abbreviations []string, name string) int {
+	name = strings.ReplaceAll(name, ".", "")
+	for i, a := range abbreviations {
+		if strings.ReplaceAll(a, ".", "") == name {
+			return i
+		}
+	}
+	return -1
+}
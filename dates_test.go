@@ -0,0 +1,75 @@
+package paasaathai
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestFormatThaiDate(c *C) {
+	t := time.Date(2026, time.October, 18, 10, 45, 0, 0, time.UTC)
+	c.Check(FormatThaiDate(t), Equals, "18 ตุลาคม 2569")
+	c.Check(ThaiDateFormat{AbbreviatedMonth: true, ShortYear: true}.Format(t),
+		Equals, "18 ต.ค. 69")
+	c.Check(ThaiDateFormat{Weekday: true, Era: true}.Format(t),
+		Equals, "วันอาทิตย์ที่ 18 ตุลาคม พ.ศ. 2569")
+	c.Check(ThaiDateFormat{AbbreviatedMonth: true, Time: true, ThaiDigits: true}.Format(t),
+		Equals, "๑๘ ต.ค. ๒๕๖๙ ๑๐:๔๕ น.")
+
+	c.Check(FormatThaiDate(time.Date(2001, time.January, 5, 0, 0, 0, 0, time.UTC)),
+		Equals, "5 มกราคม 2544")
+}
+
+func (s *MySuite) TestParseThaiDate(c *C) {
+	date := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	for _, text := range []string{
+		"18 ตุลาคม 2569",
+		"18 ต.ค. 69",
+		"18 ตค 2569",
+		"๑๘ ตุลาคม ๒๕๖๙",
+		"วันอาทิตย์ที่ 18 ตุลาคม พ.ศ. 2569",
+		"วันอาทิตย์ ที่ 18 ต.ค. 69",
+		"อา. 18 ต.ค. 69",
+		"18 ตุลาคม ค.ศ. 2026",
+	} {
+		t, err := ParseThaiDate(text, time.UTC)
+		c.Check(err, IsNil, Commentf("%s", text))
+		c.Check(t.Equal(date), Equals, true, Commentf("%s: %s", text, t))
+	}
+
+	t, err := ParseThaiDate("18 ต.ค. 2569 10.45 น.", time.UTC)
+	c.Check(err, IsNil)
+	c.Check(t.Equal(date.Add(10*time.Hour+45*time.Minute)), Equals, true)
+
+	t, err = ParseThaiDate("18 ต.ค. 2569", nil)
+	c.Check(err, IsNil)
+	c.Check(t.Location(), Equals, time.Local)
+}
+
+func (s *MySuite) TestParseThaiDateRoundTrip(c *C) {
+	t := time.Date(2024, time.February, 29, 23, 5, 0, 0, time.UTC)
+	for _, format := range []ThaiDateFormat{
+		{Time: true},
+		{Weekday: true, AbbreviatedMonth: true, ShortYear: true, Era: true,
+			Time: true, ThaiDigits: true},
+	} {
+		parsed, err := ParseThaiDate(format.Format(t), time.UTC)
+		c.Check(err, IsNil)
+		c.Check(parsed.Equal(t), Equals, true)
+	}
+}
+
+func (s *MySuite) TestParseThaiDateErrors(c *C) {
+	for _, text := range []string{
+		"",
+		"18 October 2569",
+		"31 กันยายน 2569",
+		"วันจันทร์ที่ 18 ตุลาคม 2569",
+		"18 ตุลาคม",
+		"18 ตุลาคม 2569 25:00 น.",
+		"18 ตุลาคม 2569 เช้า",
+	} {
+		_, err := ParseThaiDate(text, time.UTC)
+		c.Check(err, NotNil, Commentf("%s", text))
+	}
+}
@@ -0,0 +1,184 @@
+package paasaathai
+
+import (
+	"fmt"
+)
+
+// Why a GStackCluster is not valid Thai
+type InvalidReason int
+
+const (
+	NoInvalidReason          InvalidReason = 0
+	ReasonSaraEInvalidCombo  InvalidReason = 1
+	ReasonSaraAeInvalidCombo InvalidReason = 2
+	ReasonSoloDiacritic      InvalidReason = 3
+	ReasonSoloFinalVowel     InvalidReason = 4
+	ReasonFinalFrontVowel    InvalidReason = 5
+	ReasonSoloFrontVowel     InvalidReason = 6
+	ReasonNonModernThai      InvalidReason = 7
+
+	// No rule matched the GraphemeStack
+	ReasonUnmatched InvalidReason = 8
+)
+
+func (s InvalidReason) String() string {
+	switch s {
+	case NoInvalidReason:
+		return "NoInvalidReason"
+	case ReasonSaraEInvalidCombo:
+		return "SaraEInvalidCombo"
+	case ReasonSaraAeInvalidCombo:
+		return "SaraAeInvalidCombo"
+	case ReasonSoloDiacritic:
+		return "SoloDiacritic"
+	case ReasonSoloFinalVowel:
+		return "SoloFinalVowel"
+	case ReasonFinalFrontVowel:
+		return "FinalFrontVowel"
+	case ReasonSoloFrontVowel:
+		return "SoloFrontVowel"
+	case ReasonNonModernThai:
+		return "NonModernThai"
+	case ReasonUnmatched:
+		return "Unmatched"
+	default:
+		return fmt.Sprintf("InvalidReason(%d)", int(s))
+	}
+}
+
+// A description of the problem, for people
+func (s InvalidReason) Message() string {
+	switch s {
+	case ReasonSaraEInvalidCombo:
+		return "A sara e or sara ae can't come before a consonant with a vowel above or below it"
+	case ReasonSaraAeInvalidCombo:
+		return "This should be spelled with a sara e, not a sara ae"
+	case ReasonSoloDiacritic:
+		return "A vowel, tone mark, or sign above or below the line must be on a consonant"
+	case ReasonSoloFinalVowel:
+		return "This vowel must follow a consonant"
+	case ReasonFinalFrontVowel:
+		return "A front vowel can't end the text"
+	case ReasonSoloFrontVowel:
+		return "A front vowel must be followed by a consonant"
+	case ReasonNonModernThai:
+		return "The phinthu is not used in modern Thai spelling"
+	case ReasonUnmatched:
+		return "This is not a valid sequence of Thai characters"
+	default:
+		return ""
+	}
+}
+
+// How serious a Diagnostic is
+type Severity int
+
+const (
+	SeverityError   Severity = 0
+	SeverityWarning Severity = 1
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+func (s InvalidReason) Severity() Severity {
+	switch s {
+	case ReasonNonModernThai:
+		// Still found in Pali and Sanskrit, and older texts
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+// A problem found in Thai text
+type Diagnostic struct {
+	Reason   InvalidReason
+	Severity Severity
+
+	// The span of the problem in the original text, in bytes, with
+	// an exclusive end
+	StartByte int
+	EndByte   int
+
+	// The code points which have the problem, after normalization
+	Runes []rune
+
+	Message string
+
+	// If a GraphemeStack is malformed by itself, the error from
+	// GraphemeStack.Err
+	Err error
+}
+
+// Implements the fmt.Stringer interface
+func (s Diagnostic) String() string {
+	return fmt.Sprintf("%d-%d: %s: %s: %s", s.StartByte, s.EndByte,
+		s.Severity, s.Reason, s.Message)
+}
+
+// Make the Diagnostic for a cluster that is not valid Thai. Returns
+// false if the cluster is valid, or is not Thai at all.
+func (s *GStackCluster) Diagnostic() (Diagnostic, bool) {
+	if !s.IsThai || s.IsValidThai {
+		return Diagnostic{}, false
+	}
+	reason := s.InvalidReason
+	if reason == NoInvalidReason {
+		reason = ReasonUnmatched
+	}
+	d := Diagnostic{
+		Reason:    reason,
+		Severity:  reason.Severity(),
+		StartByte: s.StartByte,
+		EndByte:   s.EndByte,
+		Runes:     []rune(s.Text),
+		Message:   reason.Message(),
+	}
+	for _, gs := range s.stacks() {
+		if err := gs.Err(); err != nil {
+			d.Err = err
+			d.Message = err.Error()
+			break
+		}
+	}
+	return d, true
+}
+
+// Every GraphemeStack of the cluster which is set
+func (s *GStackCluster) stacks() []GraphemeStack {
+	var gstacks []GraphemeStack
+	for _, gs := range []GraphemeStack{s.FrontVowel, s.FirstConsonant,
+		s.SingleMidSign, s.InvalidThai} {
+		if gs.Text != "" {
+			gstacks = append(gstacks, gs)
+		}
+	}
+	return append(gstacks, s.Tail...)
+}
+
+// Return the Diagnostics for the clusters which are not valid Thai
+func ValidateGStackClusters(clusters []GStackCluster) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i := range clusters {
+		if d, ok := clusters[i].Diagnostic(); ok {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics
+}
+
+// Parse the text and return the Diagnostics for every part of it
+// which is not valid Thai. Text which is not Thai is not checked.
+func Validate(text string) []Diagnostic {
+	return ValidateGStackClusters(
+		NewGStackClusterParser().ParseGraphemeStacks(ParseGraphemeStacks(text)))
+}
@@ -0,0 +1,88 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestInvalidReasonString(c *C) {
+	c.Check(ReasonSoloDiacritic.String(), Equals, "SoloDiacritic")
+	c.Check(ReasonUnmatched.String(), Equals, "Unmatched")
+	c.Check(InvalidReason(99).String(), Equals, "InvalidReason(99)")
+}
+
+func (s *MySuite) TestValidateValid(c *C) {
+	c.Check(Validate("สวัสดีครับ hello"), HasLen, 0)
+}
+
+func (s *MySuite) TestValidateSoloDiacritic(c *C) {
+	// A sara i with nothing under it, and a second mai ek
+	input := "a ิ ก่่"
+	diagnostics := Validate(input)
+	c.Assert(diagnostics, HasLen, 2)
+
+	d := diagnostics[0]
+	c.Check(d.Reason, Equals, ReasonSoloDiacritic)
+	c.Check(d.Severity, Equals, SeverityError)
+	c.Check(d.StartByte, Equals, 2)
+	c.Check(d.EndByte, Equals, 5)
+	c.Check(d.Runes, DeepEquals, []rune{THAI_CHARACTER_SARA_I})
+	c.Check(d.Err, Equals, DiacriticVowelWithoutConsonantError)
+	c.Check(d.Message, Equals, DiacriticVowelWithoutConsonantError.Error())
+
+	d = diagnostics[1]
+	c.Check(d.Reason, Equals, ReasonSoloDiacritic)
+	c.Check(d.Err, Equals, DiacriticWithoutConsonantError)
+	c.Check(input[d.StartByte:d.EndByte], Equals, "่")
+}
+
+// An orphaned tone mark or vowel is Thai, but not valid Thai
+func (s *MySuite) TestSoloDiacriticIsThai(c *C) {
+	for _, text := range []string{"่", "ิ", "ุ"} {
+		gstacks := ParseGraphemeStacks(text)
+		c.Assert(gstacks, HasLen, 1)
+		c.Check(gstacks[0].IsThai(), Equals, true)
+		c.Check(gstacks[0].IsValidThai(), Equals, false)
+
+		clusters := NewGStackClusterParser().ParseGraphemeStacks(gstacks)
+		c.Assert(clusters, HasLen, 1)
+		c.Check(clusters[0].IsThai, Equals, true)
+		c.Check(clusters[0].IsValidThai, Equals, false)
+		c.Check(clusters[0].InvalidReason, Equals, ReasonSoloDiacritic)
+		c.Check(clusters[0].Repr(), Equals,
+			"<CC Invalid-Thai: "+text+" Reason: SoloDiacritic>")
+	}
+}
+
+func (s *MySuite) TestValidateReasons(c *C) {
+	diagnostics := Validate("ากฺ เ")
+	c.Assert(diagnostics, HasLen, 3)
+
+	c.Check(diagnostics[0].Reason, Equals, ReasonSoloFinalVowel)
+	c.Check(diagnostics[0].Err, IsNil)
+	c.Check(diagnostics[0].Message, Equals, ReasonSoloFinalVowel.Message())
+
+	c.Check(diagnostics[1].Reason, Equals, ReasonNonModernThai)
+	c.Check(diagnostics[1].Severity, Equals, SeverityWarning)
+	c.Check(diagnostics[1].Runes, DeepEquals,
+		[]rune{THAI_CHARACTER_KO_KAI, THAI_CHARACTER_PHINTHU})
+
+	c.Check(diagnostics[2].Reason, Equals, ReasonFinalFrontVowel)
+	c.Check(diagnostics[2].String(), Equals,
+		"10-13: error: FinalFrontVowel: A front vowel can't end the text")
+}
+
+// The fallback, when no rule matches, sets a reason
+func (s *MySuite) TestValidateUnmatched(c *C) {
+	var gcp GStackClusterParser
+	gcp.Initialize()
+	// Without rules, nothing matches
+	gcp.rules = nil
+
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("ก"))
+	c.Assert(clusters, HasLen, 1)
+	c.Check(clusters[0].InvalidReason, Equals, ReasonUnmatched)
+
+	diagnostics := ValidateGStackClusters(clusters)
+	c.Assert(diagnostics, HasLen, 1)
+	c.Check(diagnostics[0].Reason, Equals, ReasonUnmatched)
+}
@@ -5,10 +5,10 @@ go 1.19
 require (
 	github.com/gilramir/objregexp v1.0.0
 	golang.org/x/text v0.9.0
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
 )
 
 require (
 	github.com/kr/pretty v0.2.1 // indirect
 	github.com/kr/text v0.1.0 // indirect
-	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
@@ -3,6 +3,7 @@ package paasaathai
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"unicode/utf8"
 
@@ -27,6 +28,15 @@ type GraphemeStack struct {
 	// For Thai, these might be set
 	DiacriticVowel rune
 	UpperDiacritic rune
+
+	// Where the stack was found in the original input, before it was
+	// normalized. The ends are exclusive. If normalization reordered
+	// the code points, stacks that came from the same code points
+	// share the span of all of them.
+	StartByte int
+	EndByte   int
+	StartRune int
+	EndRune   int
 }
 
 // Implements the fmt.Stringer interface
@@ -55,7 +65,15 @@ func (s GraphemeStack) Repr() string {
 	return fmt.Sprintf("<GraphemeStack %s %s>", s.Text, labels)
 }
 
+// Is the stack Thai? A Thai diacritic with no consonant under it, like
+// an orphaned tone mark or upper vowel, is Thai, though it is not
+// valid Thai, so that its cluster is reported as a SoloDiacritic
+// instead of being passed over as text in another script.
 func (s GraphemeStack) IsThai() bool {
+	if s.Main == 0 {
+		// A diacritic by itself
+		return RuneIsThai(s.DiacriticVowel) || RuneIsThai(s.UpperDiacritic)
+	}
 	return RuneIsThai(s.Main)
 }
 
@@ -63,6 +81,21 @@ func (s GraphemeStack) IsValidThai() bool {
 	return s.Main != 0 && RuneIsThai(s.Main)
 }
 
+// Returns an error if the stack is malformed by itself: a diacritic
+// with no consonant to hold it.
+func (s GraphemeStack) Err() error {
+	switch {
+	case s.Main != 0:
+		return nil
+	case s.DiacriticVowel != 0:
+		return DiacriticVowelWithoutConsonantError
+	case s.UpperDiacritic != 0:
+		return DiacriticWithoutConsonantError
+	default:
+		return nil
+	}
+}
+
 func (s GraphemeStack) ToRegexString() (string, error) {
 	if !s.IsThai() {
 		return string(s.Main), nil
@@ -112,145 +145,272 @@ func MustParseSingleGraphemeStack(input string) GraphemeStack {
 	return gstacks[0]
 }
 
+// Parses GraphemeStacks on a goroutine, sending them to Chan. Wait on
+// Wg after reading Chan until it is closed. For most
+// callers, ParseGraphemeStacks or a FastGraphemeStackParser are
+// simpler, and faster.
 type GraphemeStackParser struct {
 	Chan chan GraphemeStack
 	Wg   sync.WaitGroup
 }
 
 func ParseGraphemeStacks(input string) []GraphemeStack {
-	var parser GraphemeStackParser
-	parser.GoParse(input)
+	return AppendGraphemeStacks(nil, input)
+}
+
+// Parse the input and append the stacks to dst, returning the
+// extended slice. If the input is already NFC-normalized, and dst has
+// room, nothing is allocated.
+func AppendGraphemeStacks(dst []GraphemeStack, input string) []GraphemeStack {
+	var parser FastGraphemeStackParser
+	return parser.Append(dst, input)
+}
+
+// Parses GraphemeStacks synchronously. It keeps the buffers used to
+// normalize input that is not already NFC-normalized, so that reusing
+// one parser avoids growing new buffers each time. It is not safe to
+// use from more than one goroutine at a time.
+type FastGraphemeStackParser struct {
+	nfcBuf      []byte
+	nfcSegments []nfcSegment
+}
+
+// Parse the input and append the stacks to dst, returning the
+// extended slice. The Text of each stack refers to the input if it
+// was already normalized, or otherwise to one new copy of the
+// normalized input.
+func (s *FastGraphemeStackParser) Append(dst []GraphemeStack, input string) []GraphemeStack {
+	iter := s.iterate(input)
+	if dst == nil {
+		// Most Thai stacks are one code point, of 3 bytes
+		dst = make([]GraphemeStack, 0, len(iter.normalized)/3+1)
+	}
+	for !iter.done() {
+		dst = append(dst, iter.next())
+	}
+	return dst
+}
 
-	gstacks := make([]GraphemeStack, 0, len(input))
-	for g := range parser.Chan {
-		gstacks = append(gstacks, g)
+func (s *FastGraphemeStackParser) iterate(input string) graphemeStackIter {
+	iter := graphemeStackIter{
+		original:   input,
+		normalized: input,
+	}
+	// QuickSpanString, unlike IsNormalString, does not allocate
+	if n := norm.NFC.QuickSpanString(input); n != len(input) {
+		s.nfcBuf, s.nfcSegments = normalizeWithSegments(
+			s.nfcBuf[:0], s.nfcSegments[:0], input, n)
+		iter.normalized = string(s.nfcBuf)
+		iter.segments = s.nfcSegments
+	}
+	return iter
+}
+
+// A piece of the normalized text, which came from one NFC normalization
+// segment of the original text. Segments are made of a starter and the
+// combining code points that follow it.
+type nfcSegment struct {
+	normalizedStart int
+	originalStart   int
+
+	// Did normalization change this segment?
+	changed bool
+}
+
+// Append the NFC normalization of the input to buf, and append the
+// segments to segments. The first n bytes of the input are already
+// known to be normalized.
+func normalizeWithSegments(buf []byte, segments []nfcSegment, input string, n int) ([]byte, []nfcSegment) {
+	buf = append(buf, input[:n]...)
+	if n > 0 {
+		segments = append(segments, nfcSegment{})
 	}
 
-	parser.Wg.Wait()
-	return gstacks
+	var iter norm.Iter
+	iter.InitString(norm.NFC, input[n:])
+	for !iter.Done() {
+		start := n + iter.Pos()
+		out := iter.Next()
+		end := n + iter.Pos()
+		segments = append(segments, nfcSegment{
+			normalizedStart: len(buf),
+			originalStart:   start,
+			changed:         string(out) != input[start:end],
+		})
+		buf = append(buf, out...)
+	}
+	return buf, segments
+}
+
+// Steps through the GraphemeStacks of an input, mapping their
+// positions in the normalized text back to the original text.
+type graphemeStackIter struct {
+	original   string
+	normalized string
+
+	// nil if the original was already normalized
+	segments []nfcSegment
+
+	// The position in the normalized text
+	pos int
+
+	// The number of code points before a byte offset in the original,
+	// kept so that the original is only counted once
+	runeByte  int
+	runeCount int
+}
+
+func (s *graphemeStackIter) done() bool {
+	return s.pos >= len(s.normalized)
+}
+
+func (s *graphemeStackIter) next() GraphemeStack {
+	gs, size := nextGraphemeStack(s.normalized[s.pos:])
+	gs.StartByte = s.originalOffset(s.pos, false)
+	gs.EndByte = s.originalOffset(s.pos+size, true)
+	s.pos += size
+
+	if gs.StartByte >= s.runeByte {
+		s.runeCount += utf8.RuneCountInString(s.original[s.runeByte:gs.StartByte])
+		s.runeByte = gs.StartByte
+	}
+	gs.StartRune = s.runeCount
+	gs.EndRune = s.runeCount + utf8.RuneCountInString(s.original[gs.StartByte:gs.EndByte])
+	return gs
+}
+
+// Map an offset in the normalized text to the original text. An offset
+// inside a segment that normalization changed has no exact match; it is
+// mapped to the start of the segment, or, for the end of a span, to the
+// end of the segment.
+func (s *graphemeStackIter) originalOffset(pos int, isEnd bool) int {
+	if s.segments == nil {
+		return pos
+	}
+	if pos >= len(s.normalized) {
+		return len(s.original)
+	}
+	k := sort.Search(len(s.segments), func(k int) bool {
+		return s.segments[k].normalizedStart > pos
+	}) - 1
+	seg := &s.segments[k]
+	switch {
+	case pos == seg.normalizedStart:
+		return seg.originalStart
+	case !seg.changed:
+		return seg.originalStart + pos - seg.normalizedStart
+	case isEnd && k+1 < len(s.segments):
+		return s.segments[k+1].originalStart
+	case isEnd:
+		return len(s.original)
+	default:
+		return seg.originalStart
+	}
 }
 
 func (s *GraphemeStackParser) GoParse(input string) {
 	s.Chan = make(chan GraphemeStack)
 
-	normalizedInput := norm.NFC.String(input)
+	var parser FastGraphemeStackParser
 	s.Wg.Add(1)
-	go s.parse(normalizedInput)
+	go s.parse(parser.iterate(input))
 }
 
-// TODO
-// should convert e e to ae
-//33026. เเละ in data/best/news.zip(news/news_00038.txt) line 15 item 75
-//	THAI_CHARACTER_SARA_E, THAI_CHARACTER_SARA_E, THAI_CHARACTER_LO_LING, THAI_CHARACTER_SARA_A
-
-func (s *GraphemeStackParser) parse(input string) {
+func (s *GraphemeStackParser) parse(iter graphemeStackIter) {
 	defer close(s.Chan)
 	defer s.Wg.Done()
 
-	// Check the string (array of bytes for the UTF-8 encoding)
-	for i := 0; i < len(input); {
-		// The Unicode library notation of "boundaries" doesn't handle Thai
-		// the way we need it to. Implement it ourselves.
-		r1, r1sz := utf8.DecodeRuneInString(input[i:])
-
-		// How many UTF-8 bytes have we decoded?
-		decodedBytes := r1sz
+	for !iter.done() {
+		s.Chan <- iter.next()
+	}
+}
 
-		gs := GraphemeStack{}
+// The most code points that can be read to find the end of one
+// GraphemeStack: a consonant, a diacritic vowel, and an upper diacritic.
+const graphemeStackLookahead = 3
+
+// Parse the GraphemeStack at the start of the input, which must be
+// NFC-normalized and not empty. Returns the stack and the number of
+// bytes of input it used.
+func nextGraphemeStack(input string) (GraphemeStack, int) {
+	// The Unicode library notation of "boundaries" doesn't handle Thai
+	// the way we need it to. Implement it ourselves.
+	r1, r1sz := utf8.DecodeRuneInString(input)
+
+	// How many UTF-8 bytes have we decoded?
+	decodedBytes := r1sz
+
+	gs := GraphemeStack{}
+
+	invalidThai := false
+	if RuneIsUpperPositionVowel(r1) || RuneIsLowerPositionVowel(r1) {
+		gs.DiacriticVowel = r1
+		invalidThai = true
+	} else if RuneIsToneMark(r1) || RuneIsUpperPositionSign(r1) {
+		gs.UpperDiacritic = r1
+		invalidThai = true
+	} else {
+		gs.Main = r1
+	}
 
-		invalidThai := false
-		if RuneIsUpperPositionVowel(r1) || RuneIsLowerPositionVowel(r1) {
-			gs.DiacriticVowel = r1
-			invalidThai = true
-		} else if RuneIsToneMark(r1) || RuneIsUpperPositionSign(r1) {
-			gs.UpperDiacritic = r1
-			invalidThai = true
-		} else {
-			gs.Main = r1
-		}
+	// Not Thai? Next!
+	if !RuneIsThai(r1) || invalidThai {
+		gs.Text = input[:decodedBytes]
+		return gs, decodedBytes
+	}
 
+	// If this Thai rune could have a diacritic on it, check.
+	// A Thai code point needs 3 bytes to be encoded in UTF-8; do we have
+	// enough for another code point?
+	if RuneIsConsonant(r1) && len(input)-decodedBytes >= 3 {
+		r2, r2sz := utf8.DecodeRuneInString(input[decodedBytes:])
 		// Not Thai? Next!
-		if !RuneIsThai(r1) || invalidThai {
-			gs.Text = input[i : i+decodedBytes]
-			s.Chan <- gs
-			i += decodedBytes
-			continue
+		if !RuneIsThai(r2) {
+			gs.Text = input[:decodedBytes]
+			return gs, decodedBytes
 		}
 
-		// If this Thai rune could have a diacritic on it, check.
-		// A Thai code point needs 3 bytes to be encoded in UTF-8; do we have
-		// enough for another code point?
-		if RuneIsConsonant(r1) && len(input)-(i+decodedBytes) >= 3 {
-			r2, r2sz := utf8.DecodeRuneInString(input[i+decodedBytes:])
-			// Not Thai? Next!
-			if !RuneIsThai(r2) {
-				gs.Text = input[i : i+decodedBytes]
-				s.Chan <- gs
-				i += decodedBytes
-				continue
-			}
-
-			if RuneIsUpperPositionVowel(r2) || RuneIsLowerPositionVowel(r2) {
-				decodedBytes += r2sz
-				gs.DiacriticVowel = r2
-			} else if RuneIsToneMark(r2) || RuneIsUpperPositionSign(r2) {
-				decodedBytes += r2sz
-				gs.UpperDiacritic = r2
-				// This GraphemeStack is only made of 2 code
-				// points, because nothing can follow the
-				// UpperDiacritic
-				gs.Text = input[i : i+decodedBytes]
-				s.Chan <- gs
-				i += decodedBytes
-				continue
-			} else {
-				// This GraphemeStack is only made of one code
-				// point, because r2 cannot be stacked
-				// over/under r1
-				gs.Text = input[i : i+decodedBytes]
-				s.Chan <- gs
-				i += decodedBytes
-				continue
-			}
+		if RuneIsUpperPositionVowel(r2) || RuneIsLowerPositionVowel(r2) {
+			decodedBytes += r2sz
+			gs.DiacriticVowel = r2
+		} else if RuneIsToneMark(r2) || RuneIsUpperPositionSign(r2) {
+			decodedBytes += r2sz
+			gs.UpperDiacritic = r2
+			// This GraphemeStack is only made of 2 code
+			// points, because nothing can follow the
+			// UpperDiacritic
+			gs.Text = input[:decodedBytes]
+			return gs, decodedBytes
+		} else {
+			// This GraphemeStack is only made of one code
+			// point, because r2 cannot be stacked
+			// over/under r1
+			gs.Text = input[:decodedBytes]
+			return gs, decodedBytes
+		}
 
-			// We have 2 code points. Is there a third?
-			// An upper or lower vowel can still take a tone mark
-			// or other upper diacritic
-			if (RuneIsLowerPositionVowel(r2) || RuneIsUpperPositionVowel(r2)) && len(input)-(i+decodedBytes) >= 3 {
-				r3, r3sz := utf8.DecodeRuneInString(input[i+decodedBytes:])
-				if RuneIsToneMark(r3) || RuneIsUpperPositionSign(r3) {
-					decodedBytes += r3sz
-					gs.UpperDiacritic = r3
-				}
-			}
-		} else if r1 == THAI_CHARACTER_SARA_E && len(input)-(i+decodedBytes) >= 3 {
-
-			r2, r2sz := utf8.DecodeRuneInString(input[i+decodedBytes:])
-			// Not Thai? Next!
-			if !RuneIsThai(r2) {
-				gs.Text = input[i : i+decodedBytes]
-				s.Chan <- gs
-				i += decodedBytes
-				continue
-			} else if r2 == THAI_CHARACTER_SARA_E {
-				// correct spelling mistakes; 2 sara e's == 1 sara ae
-				decodedBytes += r2sz
-				gs.Main = THAI_CHARACTER_SARA_AE
-				gs.Text = string(THAI_CHARACTER_SARA_AE)
-				s.Chan <- gs
-				i += decodedBytes
-				continue
-			} else {
-				gs.Text = input[i : i+decodedBytes]
-				s.Chan <- gs
-				i += decodedBytes
-				continue
+		// We have 2 code points. Is there a third?
+		// An upper or lower vowel can still take a tone mark
+		// or other upper diacritic
+		if len(input)-decodedBytes >= 3 {
+			r3, r3sz := utf8.DecodeRuneInString(input[decodedBytes:])
+			if RuneIsToneMark(r3) || RuneIsUpperPositionSign(r3) {
+				decodedBytes += r3sz
+				gs.UpperDiacritic = r3
 			}
 		}
-
-		// At this point we have 2 or 3 code points.
-		gs.Text = input[i : i+decodedBytes]
-		s.Chan <- gs
-		i += decodedBytes
+	} else if r1 == THAI_CHARACTER_SARA_E && len(input)-decodedBytes >= 3 {
+		r2, r2sz := utf8.DecodeRuneInString(input[decodedBytes:])
+		if r2 == THAI_CHARACTER_SARA_E {
+			// correct spelling mistakes; 2 sara e's == 1 sara ae
+			decodedBytes += r2sz
+			gs.Main = THAI_CHARACTER_SARA_AE
+			gs.Text = string(THAI_CHARACTER_SARA_AE)
+			return gs, decodedBytes
+		}
 	}
+
+	// At this point we have 1, 2 or 3 code points.
+	gs.Text = input[:decodedBytes]
+	return gs, decodedBytes
 }
@@ -0,0 +1,161 @@
+package paasaathai
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A bufio.SplitFunc that splits text into the UTF-8 of each
+// GraphemeStack. A stack that is cut off at the end of the data
+// is not returned until more data arrives, so stacks are never split
+// across chunk boundaries. The text must already be NFC-normalized;
+// wrap the reader with norm.NFC.Reader if it might not be.
+//
+// Pass each token to MustParseSingleGraphemeStack to get the
+// GraphemeStack.
+func ScanGraphemeStacks(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	// Only the first few code points are needed to find the end of
+	// the first stack. If they are not all here, wait for more.
+	n, complete := leadingRunesLen(data, graphemeStackLookahead)
+	if !complete && !atEOF {
+		return 0, nil, nil
+	}
+	if !complete {
+		n = len(data)
+	}
+
+	_, size := nextGraphemeStack(string(data[:n]))
+	return size, data[:size], nil
+}
+
+// Returns the number of bytes in the first count code points of the data,
+// and whether all of those code points were there.
+func leadingRunesLen(data []byte, count int) (int, bool) {
+	n := 0
+	for i := 0; i < count; i++ {
+		if !utf8.FullRune(data[n:]) {
+			return n, false
+		}
+		_, size := utf8.DecodeRune(data[n:])
+		n += size
+	}
+	return n, true
+}
+
+// A bufio.SplitFunc that splits text into chunks that can be
+// normalized and parsed on their own: each chunk ends just before a
+// code point that always begins both a GraphemeStack and an NFC
+// normalization segment.
+//
+// A run of sara e is cut between pairs, since each pair is a stack. A
+// run of diacritics longer than a stack can hold is cut after every
+// graphemeStackLookahead-1 of them, so that a run longer than the
+// scanner's buffer can still be read; such a run is not valid Thai,
+// and it may be normalized differently than it would be as a whole.
+func scanGraphemeStackChunks(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	cut := 0
+	// The number of diacritics, and of sara e, in a row
+	diacritics, saraE := 0, 0
+	for i := 0; i < len(data) && utf8.FullRune(data[i:]); {
+		r, size := utf8.DecodeRune(data[i:])
+		switch {
+		case r == THAI_CHARACTER_SARA_E:
+			if i > 0 && saraE%2 == 0 {
+				cut = i
+			}
+			saraE++
+			diacritics = 0
+
+		case !runeJoinsGraphemeStack(r) && norm.NFC.Properties(data[i:]).BoundaryBefore():
+			if i > 0 {
+				cut = i
+			}
+			diacritics, saraE = 0, 0
+
+		default:
+			diacritics++
+			saraE = 0
+			if diacritics == graphemeStackLookahead && i > 0 {
+				// End the chunk here, so that no chunk has a long run
+				// for the normalizer to break up
+				return i, data[:i], nil
+			}
+		}
+		i += size
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	if cut == 0 {
+		return 0, nil, nil
+	}
+	return cut, data[:cut], nil
+}
+
+// Does the code point join the GraphemeStack before it?
+func runeJoinsGraphemeStack(r rune) bool {
+	return RuneIsUpperPositionVowel(r) || RuneIsLowerPositionVowel(r) ||
+		RuneIsToneMark(r) || RuneIsUpperPositionSign(r)
+}
+
+// Parses GraphemeStacks from a reader incrementally, so that the
+// whole text never needs to be in memory. The text is NFC-normalized
+// as it is read. The offsets in each stack are from the start of the
+// reader.
+type GraphemeStackReader struct {
+	scanner *bufio.Scanner
+	parser  FastGraphemeStackParser
+
+	// The stacks of the current chunk which have not been returned
+	gstacks []GraphemeStack
+	next    int
+
+	// The offsets of the current chunk
+	startByte int
+	startRune int
+}
+
+func NewGraphemeStackReader(r io.Reader) *GraphemeStackReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanGraphemeStackChunks)
+	return &GraphemeStackReader{
+		scanner: scanner,
+	}
+}
+
+// Return the next GraphemeStack. At the end of the text, the error
+// is io.EOF.
+func (s *GraphemeStackReader) Next() (GraphemeStack, error) {
+	for s.next == len(s.gstacks) {
+		if len(s.gstacks) > 0 {
+			last := &s.gstacks[len(s.gstacks)-1]
+			s.startByte = last.EndByte
+			s.startRune = last.EndRune
+		}
+		if !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return GraphemeStack{}, err
+			}
+			return GraphemeStack{}, io.EOF
+		}
+		s.gstacks = s.parser.Append(s.gstacks[:0], s.scanner.Text())
+		s.next = 0
+		for i := range s.gstacks {
+			gs := &s.gstacks[i]
+			gs.StartByte += s.startByte
+			gs.EndByte += s.startByte
+			gs.StartRune += s.startRune
+			gs.EndRune += s.startRune
+		}
+	}
+	gs := s.gstacks[s.next]
+	s.next++
+	return gs, nil
+}
@@ -0,0 +1,100 @@
+package paasaathai
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing/iotest"
+
+	. "gopkg.in/check.v1"
+)
+
+func readAllGraphemeStacks(r io.Reader) ([]GraphemeStack, error) {
+	reader := NewGraphemeStackReader(r)
+	var gstacks []GraphemeStack
+	for {
+		gs, err := reader.Next()
+		if err == io.EOF {
+			return gstacks, nil
+		}
+		if err != nil {
+			return gstacks, err
+		}
+		gstacks = append(gstacks, gs)
+	}
+}
+
+func (s *MySuite) TestGraphemeStackReader(c *C) {
+	input := "ผู้ใหญ่ a เเละ ที่นี่ กิ๊ก"
+	expected := ParseGraphemeStacks(input)
+
+	// Feed it one byte at a time, so that every stack
+	// is split between reads
+	gstacks, err := readAllGraphemeStacks(
+		iotest.OneByteReader(strings.NewReader(input)))
+	c.Assert(err, IsNil)
+	c.Check(gstacks, DeepEquals, expected)
+}
+
+func (s *MySuite) TestGraphemeStackReaderNormalizes(c *C) {
+	// THAI_CHARACTER_KO_KAI, THAI_CHARACTER_MAI_EK, THAI_CHARACTER_SARA_U
+	// is put into the canonical order, with the vowel first
+	gstacks, err := readAllGraphemeStacks(
+		iotest.OneByteReader(strings.NewReader("\u0e01\u0e48\u0e38")))
+	c.Assert(err, IsNil)
+	c.Assert(len(gstacks), Equals, 1)
+	c.Check(gstacks[0].Main, Equals, THAI_CHARACTER_KO_KAI)
+	c.Check(gstacks[0].DiacriticVowel, Equals, THAI_CHARACTER_SARA_U)
+	c.Check(gstacks[0].UpperDiacritic, Equals, THAI_CHARACTER_MAI_EK)
+}
+
+func (s *MySuite) TestGraphemeStackReaderError(c *C) {
+	r := io.MultiReader(strings.NewReader("กา"),
+		iotest.ErrReader(io.ErrUnexpectedEOF))
+	_, err := readAllGraphemeStacks(r)
+	c.Check(err, Equals, io.ErrUnexpectedEOF)
+}
+
+func (s *MySuite) TestScanGraphemeStacks(c *C) {
+	scanner := bufio.NewScanner(iotest.HalfReader(strings.NewReader("กี่ข้อ")))
+	scanner.Split(ScanGraphemeStacks)
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+	c.Assert(scanner.Err(), IsNil)
+	c.Check(tokens, DeepEquals, []string{"กี่", "ข้", "อ"})
+}
+
+func (s *MySuite) TestGraphemeStackReaderOffsets(c *C) {
+	input := "เเละ é กุ่ผู้ใหญ่"
+	gstacks, err := readAllGraphemeStacks(
+		iotest.OneByteReader(strings.NewReader(input)))
+	c.Assert(err, IsNil)
+	c.Check(gstacks, DeepEquals, ParseGraphemeStacks(input))
+}
+
+// Runs longer than the scanner's buffer are cut, instead of failing
+// with bufio.ErrTooLong
+func (s *MySuite) TestGraphemeStackReaderLongRuns(c *C) {
+	// Every pair of sara e is a stack, so the run is parsed as a
+	// whole would be
+	input := "ก" + strings.Repeat("เ", bufio.MaxScanTokenSize) + "ก"
+	gstacks, err := readAllGraphemeStacks(strings.NewReader(input))
+	c.Assert(err, IsNil)
+	c.Check(gstacks, DeepEquals, ParseGraphemeStacks(input))
+
+	// A run of tone marks is not valid Thai, but it is still read
+	input = "ก" + strings.Repeat("่", bufio.MaxScanTokenSize) + "ก"
+	gstacks, err = readAllGraphemeStacks(strings.NewReader(input))
+	c.Assert(err, IsNil)
+	var sb strings.Builder
+	for i := range gstacks {
+		sb.WriteString(gstacks[i].Text)
+		if i > 0 {
+			c.Assert(gstacks[i].StartByte, Equals, gstacks[i-1].EndByte)
+		}
+	}
+	c.Check(sb.String(), Equals, input)
+	c.Check(gstacks[len(gstacks)-1].Text, Equals, "ก")
+}
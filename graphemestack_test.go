@@ -1,6 +1,8 @@
 package paasaathai
 
 import (
+	"testing"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -200,3 +202,134 @@ func (s *MySuite) TestToRegexString02(c *C) {
 	c.Assert(err, IsNil)
 	c.Check(rs, Equals, "[:no nu:]")
 }
+
+// The synchronous parser and the channel parser agree
+func (s *MySuite) TestGraphemeStackParsersAgree(c *C) {
+	input := "ผู้ใหญ่ a เเละ ที่นี่ กิ๊ก\u0e01\u0e48\u0e38"
+
+	var parser GraphemeStackParser
+	parser.GoParse(input)
+	var expected []GraphemeStack
+	for gs := range parser.Chan {
+		expected = append(expected, gs)
+	}
+	parser.Wg.Wait()
+
+	c.Check(ParseGraphemeStacks(input), DeepEquals, expected)
+
+	var fast FastGraphemeStackParser
+	gstacks := fast.Append([]GraphemeStack{{Text: "x", Main: 'x'}}, input)
+	c.Assert(len(gstacks), Equals, len(expected)+1)
+	c.Check(gstacks[1:], DeepEquals, expected)
+}
+
+func (s *MySuite) TestAppendGraphemeStacksAllocations(c *C) {
+	input := "สวัสดีครับ"
+	dst := make([]GraphemeStack, 0, 32)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = AppendGraphemeStacks(dst[:0], input)
+	})
+	c.Check(allocs, Equals, 0.0)
+	c.Check(len(dst), Equals, 7)
+}
+
+const benchmarkGraphemeStacksInput = "ค้นหาร้านอาหารใกล้ฉัน"
+
+func BenchmarkGoParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var parser GraphemeStackParser
+		parser.GoParse(benchmarkGraphemeStacksInput)
+		for range parser.Chan {
+		}
+		parser.Wg.Wait()
+	}
+}
+
+func BenchmarkParseGraphemeStacks(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParseGraphemeStacks(benchmarkGraphemeStacksInput)
+	}
+}
+
+func BenchmarkAppendGraphemeStacks(b *testing.B) {
+	b.ReportAllocs()
+	dst := make([]GraphemeStack, 0, 64)
+	for i := 0; i < b.N; i++ {
+		dst = AppendGraphemeStacks(dst[:0], benchmarkGraphemeStacksInput)
+	}
+}
+
+func BenchmarkFastGraphemeStackParserNotNormalized(b *testing.B) {
+	b.ReportAllocs()
+	// THAI_CHARACTER_KO_KAI, THAI_CHARACTER_MAI_EK, THAI_CHARACTER_SARA_U
+	// needs to be reordered
+	input := benchmarkGraphemeStacksInput + "\u0e01\u0e48\u0e38"
+	var parser FastGraphemeStackParser
+	dst := make([]GraphemeStack, 0, 64)
+	for i := 0; i < b.N; i++ {
+		dst = parser.Append(dst[:0], input)
+	}
+}
+
+func (s *MySuite) TestGraphemeStackOffsets(c *C) {
+	input := "é ที่"
+	gstacks := ParseGraphemeStacks(input)
+	c.Assert(len(gstacks), Equals, 3)
+
+	c.Check(gstacks[0].StartByte, Equals, 0)
+	c.Check(gstacks[0].EndByte, Equals, 2)
+	c.Check(gstacks[1].StartByte, Equals, 2)
+	c.Check(gstacks[1].EndByte, Equals, 3)
+	c.Check(gstacks[1].StartRune, Equals, 1)
+	c.Check(gstacks[1].EndRune, Equals, 2)
+	c.Check(gstacks[2].StartByte, Equals, 3)
+	c.Check(gstacks[2].EndByte, Equals, 12)
+	c.Check(gstacks[2].StartRune, Equals, 2)
+	c.Check(gstacks[2].EndRune, Equals, 5)
+	c.Check(input[gstacks[2].StartByte:gstacks[2].EndByte], Equals, "ที่")
+}
+
+// The 2 sara e's that become one sara ae keep the span of both
+func (s *MySuite) TestGraphemeStackOffsetsSaraAe(c *C) {
+	input := "aเเละ"
+	gstacks := ParseGraphemeStacks(input)
+	c.Assert(len(gstacks), Equals, 4)
+
+	c.Check(gstacks[1].Text, Equals, "แ")
+	c.Check(gstacks[1].StartByte, Equals, 1)
+	c.Check(gstacks[1].EndByte, Equals, 7)
+	c.Check(gstacks[1].StartRune, Equals, 1)
+	c.Check(gstacks[1].EndRune, Equals, 3)
+	c.Check(gstacks[2].StartByte, Equals, 7)
+	c.Check(gstacks[2].StartRune, Equals, 3)
+}
+
+// Offsets are in the input from before it was normalized
+func (s *MySuite) TestGraphemeStackOffsetsNormalized(c *C) {
+	// "e" and a combining acute accent, which become "é", and
+	// THAI_CHARACTER_KO_KAI, THAI_CHARACTER_MAI_EK, THAI_CHARACTER_SARA_U,
+	// which are reordered
+	input := "éxกุ่า"
+	gstacks := ParseGraphemeStacks(input)
+	c.Assert(len(gstacks), Equals, 4)
+
+	c.Check(gstacks[0].Text, Equals, "é")
+	c.Check(gstacks[0].StartByte, Equals, 0)
+	c.Check(gstacks[0].EndByte, Equals, 3)
+	c.Check(gstacks[0].EndRune, Equals, 2)
+
+	c.Check(gstacks[1].StartByte, Equals, 3)
+	c.Check(gstacks[1].EndByte, Equals, 4)
+	c.Check(gstacks[1].StartRune, Equals, 2)
+
+	c.Check(gstacks[2].DiacriticVowel, Equals, THAI_CHARACTER_SARA_U)
+	c.Check(gstacks[2].StartByte, Equals, 4)
+	c.Check(gstacks[2].EndByte, Equals, 13)
+	c.Check(gstacks[2].StartRune, Equals, 3)
+	c.Check(gstacks[2].EndRune, Equals, 6)
+
+	c.Check(gstacks[3].StartByte, Equals, 13)
+	c.Check(gstacks[3].EndByte, Equals, 16)
+	c.Check(gstacks[3].StartRune, Equals, 6)
+	c.Check(gstacks[3].EndRune, Equals, 7)
+}
@@ -13,22 +13,14 @@ package paasaathai
 // Becker, ISBN 1-887521-00 3
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/gilramir/objregexp"
 )
 
-const (
-	ReasonSaraEInvalidCombo  = 1
-	ReasonSaraAeInvalidCombo = 2
-	ReasonSoloDiacritic      = 3
-	ReasonSoloFinalVowel     = 4
-	ReasonFinalFrontVowel    = 5
-	ReasonSoloFrontVowel     = 6
-	ReasonNonModernThai      = 7
-)
-
 type GStackCluster struct {
 	// The UTF-8 string in this cluster
 	Text string
@@ -40,7 +32,7 @@ type GStackCluster struct {
 	IsValidThai bool
 
 	// If invalid, this is the reason
-	InvalidReason int
+	InvalidReason InvalidReason
 
 	// These are the well-known parts of a cluster
 	// Not always set, but if so, this is the vowel that
@@ -62,6 +54,13 @@ type GStackCluster struct {
 
 	// The name of the rule that created this cluster.
 	MatchingRule string
+
+	// Where the cluster was found in the original input, before it
+	// was normalized; see GraphemeStack
+	StartByte int
+	EndByte   int
+	StartRune int
+	EndRune   int
 }
 
 func (s *GStackCluster) Repr() string {
@@ -85,7 +84,7 @@ func (s *GStackCluster) Repr() string {
 				return result + ">"
 			}
 		} else {
-			return fmt.Sprintf("<CC Invalid-Thai: %s Reason: %d>", s.Text, s.InvalidReason)
+			return fmt.Sprintf("<CC Invalid-Thai: %s Reason: %s>", s.Text, s.InvalidReason)
 		}
 	} else {
 		return fmt.Sprintf("<CC Not-Thai: %s>", s.Text)
@@ -95,12 +94,14 @@ func (s *GStackCluster) Repr() string {
 func makeCluster(input []GraphemeStack) GStackCluster {
 	isThai := true
 	isValidThai := true
+	reason := NoInvalidReason
 	text := ""
 	for _, gs := range input {
 		text += gs.Text
 		if gs.IsThai() {
 			if !gs.IsValidThai() {
 				isValidThai = false
+				reason = ReasonSoloDiacritic
 			}
 		} else {
 			isThai = false
@@ -108,16 +109,59 @@ func makeCluster(input []GraphemeStack) GStackCluster {
 		}
 	}
 	tcc := GStackCluster{
-		Text:        text,
-		IsThai:      isThai,
-		IsValidThai: isValidThai,
+		Text:          text,
+		IsThai:        isThai,
+		IsValidThai:   isValidThai,
+		InvalidReason: reason,
+	}
+	if len(input) > 0 {
+		tcc.StartByte = input[0].StartByte
+		tcc.EndByte = input[len(input)-1].EndByte
+		tcc.StartRune = input[0].StartRune
+		tcc.EndRune = input[len(input)-1].EndRune
 	}
 	// More TODO
 	return tcc
 }
 
+// Parses GraphemeStacks into GStackClusters. After Initialize, it is
+// not changed by parsing, so one parser can be used by many goroutines
+// at once; NewGStackClusterParser returns such a shared parser.
 type GStackClusterParser struct {
 	compiler objregexp.Compiler[GraphemeStack]
+
+	// This parser's copies of the rules, in the order they are tried,
+	// with their regexes compiled by this parser's compiler
+	rules []TccRule
+
+	// The rules given to AddRule
+	addedRules []*TccRule
+
+	// Set by WithTracer
+	tracer Tracer
+
+	// Set by Initialize; the rules can't be changed after that
+	initialized bool
+}
+
+// Returned by GStackClusterParser.Initialize when it is called more
+// than once, as on the parser from NewGStackClusterParser
+var ParserAlreadyInitializedError = errors.New("The GStackClusterParser is already initialized")
+
+var sharedGStackClusterParser GStackClusterParser
+var sharedGStackClusterParserOnce sync.Once
+
+// Returns a parser whose rules were compiled once, the first time
+// it was called. It is shared by every caller, and is safe to use
+// concurrently. It is already initialized, so its rules can't be
+// changed; to add rules, start with a GStackClusterParser of your own.
+func NewGStackClusterParser() *GStackClusterParser {
+	sharedGStackClusterParserOnce.Do(func() {
+		if err := sharedGStackClusterParser.Initialize(); err != nil {
+			panic(err)
+		}
+	})
+	return &sharedGStackClusterParser
 }
 
 type TccRule struct {
@@ -125,6 +169,14 @@ type TccRule struct {
 	rs    string
 	ck    func(s *TccRule, input []GraphemeStack, i int, length *int, c *GStackCluster) bool
 	regex *objregexp.Regexp[GraphemeStack]
+
+	// For rules made by NewTccRule, instead of ck
+	extract TccExtractFunc
+
+	// The names of the rules that this rule must be tried before,
+	// or after
+	before []string
+	after  []string
 }
 
 func (s *TccRule) CompileWith(compiler *objregexp.Compiler[GraphemeStack]) {
@@ -226,82 +278,134 @@ func curriedHasClass(r rune) func(GraphemeStack) bool {
 	}
 }
 
-func (s *GStackClusterParser) Initialize() {
-	s.compiler.Initialize()
+// Like an identity, but only the code points are compared, not the
+// positions of the stacks
+func curriedIsStack(gs GraphemeStack) func(GraphemeStack) bool {
+	return func(g GraphemeStack) bool {
+		return g.Main == gs.Main && g.DiacriticVowel == gs.DiacriticVowel &&
+			g.UpperDiacritic == gs.UpperDiacritic
+	}
+}
+
+// Compile the classes and the rules. An error is returned if the rules
+// added with AddRule don't compile, or if the ordering constraints of
+// the rules conflict; the parser can't be used in that case. It can
+// only be called once, even if it fails; after that, it returns
+// ParserAlreadyInitializedError.
+func (s *GStackClusterParser) Initialize() error {
+	if s.initialized {
+		return ParserAlreadyInitializedError
+	}
+	s.initialized = true
+	initializeTccCompiler(&s.compiler)
+
+	// The added rules go before the catch-all consonant rule, so that
+	// it doesn't hide them; their constraints can move them from there
+	rules := make([]*TccRule, 0, len(tccRules)+len(s.addedRules))
+	for _, rule := range tccRules {
+		if rule == &r_single_consonant {
+			rules = append(rules, s.addedRules...)
+		}
+		rules = append(rules, rule)
+	}
+	ordered, err := orderTccRules(rules)
+	if err != nil {
+		s.rules = nil
+		return err
+	}
+
+	// Compile copies of the rules, so that the package's rules are
+	// never changed
+	s.rules = make([]TccRule, len(ordered))
+	for i, rule := range ordered {
+		s.rules[i] = *rule
+		s.rules[i].regex, err = s.compiler.Compile(rule.rs)
+		if err != nil {
+			s.rules = nil
+			return fmt.Errorf("Compiling rule %s: %w", rule.name, err)
+		}
+	}
+	return nil
+}
+
+// Define the classes that the rules' patterns use, and finalize the
+// compiler
+func initializeTccCompiler(compiler *objregexp.Compiler[GraphemeStack]) {
+	compiler.Initialize()
 
 	// regex classes
 	/*
-		s.compiler.MakeClass("gaaw",
+		compiler.MakeClass("gaaw",
 			func(gs GraphemeStack) bool {
 				return gs.String() == "ก็"
 			})
 	*/
-	s.compiler.MakeClass("consonant",
+	compiler.MakeClass("consonant",
 		func(gs GraphemeStack) bool {
 			return RuneIsConsonant(gs.Main)
 		})
 
-	s.compiler.MakeClass("diacritic vowel",
+	compiler.MakeClass("diacritic vowel",
 		func(gs GraphemeStack) bool {
 			return gs.DiacriticVowel != 0
 		})
 
-	s.compiler.MakeClass("has phinthu",
+	compiler.MakeClass("has phinthu",
 		func(gs GraphemeStack) bool {
 			return gs.DiacriticVowel == THAI_CHARACTER_PHINTHU
 		})
 
-	s.compiler.MakeClass("tone mark",
+	compiler.MakeClass("tone mark",
 		func(gs GraphemeStack) bool {
 			return RuneIsToneMark(gs.UpperDiacritic)
 		})
 
-	s.compiler.MakeClass("low consonant after ho hip",
+	compiler.MakeClass("low consonant after ho hip",
 		func(gs GraphemeStack) bool {
 			return LowConsonantsAllowedAfterHoHip.Has(gs.Main)
 		})
 
-	s.compiler.MakeClass("consonant before gliding lo ling",
+	compiler.MakeClass("consonant before gliding lo ling",
 		func(gs GraphemeStack) bool {
 			return ConsonantsAllowedBeforeGlidingLoLing.Has(gs.Main)
 		})
 
-	s.compiler.MakeClass("consonant before gliding ro rua",
+	compiler.MakeClass("consonant before gliding ro rua",
 		func(gs GraphemeStack) bool {
 			return ConsonantsAllowedBeforeGlidingRoRua.Has(gs.Main)
 		})
 
-	s.compiler.MakeClass("consonant before gliding wo waen",
+	compiler.MakeClass("consonant before gliding wo waen",
 		func(gs GraphemeStack) bool {
 			return ConsonantsAllowedBeforeGlidingWoWaen.Has(gs.Main)
 		})
 
-	s.compiler.MakeClass("front position vowel",
+	compiler.MakeClass("front position vowel",
 		func(gs GraphemeStack) bool {
 			return RuneIsFrontPositionVowel(gs.Main)
 		})
 
-	s.compiler.MakeClass("mid position vowel",
+	compiler.MakeClass("mid position vowel",
 		func(gs GraphemeStack) bool {
 			return RuneIsMidPositionVowel(gs.Main)
 		})
 
-	s.compiler.MakeClass("mid position sign",
+	compiler.MakeClass("mid position sign",
 		func(gs GraphemeStack) bool {
 			return RuneIsMidPositionSign(gs.Main)
 		})
 
-	s.compiler.MakeClass("upper position sign",
+	compiler.MakeClass("upper position sign",
 		func(gs GraphemeStack) bool {
 			return RuneIsUpperPositionSign(gs.UpperDiacritic)
 		})
 
-	s.compiler.MakeClass("digit",
+	compiler.MakeClass("digit",
 		func(gs GraphemeStack) bool {
 			return RuneIsDigit(gs.Main)
 		})
 
-	// regex identity classes for:
+	// regex classes, which act as identities, for:
 	// digits, non-diacritic vowels, currency, and other mid-position signs
 	// for consonant, prefix with "bare "
 	for fullName, thaiRune := range ThaiNameToRune {
@@ -321,8 +425,8 @@ func (s *GStackClusterParser) Initialize() {
 
 		name = strings.ToLower(name)
 		name = strings.ReplaceAll(name, "_", " ")
-		s.compiler.AddIdentity(name,
-			MustParseSingleGraphemeStack(string(thaiRune)))
+		compiler.MakeClass(name,
+			curriedIsStack(MustParseSingleGraphemeStack(string(thaiRune))))
 	}
 
 	// regex iidentity class for all consonants and diacritics
@@ -343,36 +447,40 @@ func (s *GStackClusterParser) Initialize() {
 
 		name = strings.ToLower(name)
 		name = strings.ReplaceAll(name, "_", " ")
-		s.compiler.MakeClass(name, curriedHasClass(thaiRune))
+		compiler.MakeClass(name, curriedHasClass(thaiRune))
 	}
 
-	s.compiler.Finalize()
-
-	r_maybe_sandwich_sara_a.CompileWith(&s.compiler)
-	r_sandwich_ia.CompileWith(&s.compiler)
-	r_sandwich_ueea_er.CompileWith(&s.compiler)
-	r_special_o_ang.CompileWith(&s.compiler)
-	r_short_o_ang.CompileWith(&s.compiler)
-	r_sara_a_aa.CompileWith(&s.compiler)
-	r_sara_uee.CompileWith(&s.compiler)
-	r_sara_ai.CompileWith(&s.compiler)
-	r_sara_ai_single_consonant.CompileWith(&s.compiler)
-	r_medial_er.CompileWith(&s.compiler)
-	r_ua.CompileWith(&s.compiler)
-	r_sara_am.CompileWith(&s.compiler)
-	r_mai_han_akat.CompileWith(&s.compiler)
-	r_sandwich_ao.CompileWith(&s.compiler)
-	r_single_diacritic_vowel.CompileWith(&s.compiler)
-	r_single_consonant.CompileWith(&s.compiler)
-	r_punctuation_or_digit.CompileWith(&s.compiler)
-	r_sanskrit.CompileWith(&s.compiler)
-	r_error_solo_diacritic.CompileWith(&s.compiler)
-	r_error_sara_e_ae.CompileWith(&s.compiler)
-	r_error_short_o_ang.CompileWith(&s.compiler)
-	r_error_solo_final_vowel.CompileWith(&s.compiler)
-	r_error_final_front_vowel.CompileWith(&s.compiler)
-	r_error_double_front_vowel.CompileWith(&s.compiler)
-	r_error_phinthu.CompileWith(&s.compiler)
+	compiler.Finalize()
+}
+
+// The built-in rules. They are tried in this order, except where
+// their before and after constraints say otherwise.
+var tccRules = []*TccRule{
+	&r_special_o_ang,
+	&r_short_o_ang,
+	&r_sandwich_ia,
+	&r_sandwich_ueea_er,
+	&r_medial_er,
+	&r_sandwich_ao,
+	&r_sara_ai,
+	&r_sara_ai_single_consonant,
+	&r_maybe_sandwich_sara_a,
+	&r_sara_a_aa,
+	&r_sara_uee,
+	&r_ua,
+	&r_sara_am,
+	&r_mai_han_akat,
+	&r_single_diacritic_vowel, // this comes after other vowels
+	&r_sanskrit,
+	&r_single_consonant, // this needs to be the last consonant rule
+	&r_punctuation_or_digit,
+	&r_error_phinthu,
+	&r_error_solo_diacritic,
+	&r_error_solo_final_vowel,
+	&r_error_final_front_vowel,
+	&r_error_double_front_vowel,
+	&r_error_sara_e_ae,
+	&r_error_short_o_ang,
 }
 
 func RuneThaiNameToRegexClassName(fullName string) (string, error) {
@@ -415,34 +523,6 @@ func (s *GStackClusterParser) ParseGraphemeStacks(input []GraphemeStack) []GStac
 	estimatedAllocation := len(input) * 2 / 3
 	clusters := make([]GStackCluster, 0, estimatedAllocation)
 
-	rules := []TccRule{
-		r_special_o_ang,    // must come before short_o_ang
-		r_short_o_ang,      // must come before maybe_sandwich_sara_a
-		r_sandwich_ia,      // must come before maybe_sandwich_sara_a
-		r_sandwich_ueea_er, // must come before maybe_sandwich_sara_a
-		r_medial_er,        // must come before maybe_sandwich_sara_a
-		r_sandwich_ao,      // must come before maybe_sandwich_sara_a
-		r_sara_ai,          // must come before sara_ai_single_consonant
-		r_sara_ai_single_consonant,
-		r_maybe_sandwich_sara_a,
-		r_sara_a_aa,
-		r_sara_uee,
-		r_ua,
-		r_sara_am,
-		r_mai_han_akat,
-		r_single_diacritic_vowel, // this comes after other vowels
-		r_sanskrit,               // this must come before single_consonant
-		r_single_consonant,       // this needs to be the last consonant rule
-		r_punctuation_or_digit,
-		r_error_phinthu,
-		r_error_solo_diacritic,
-		r_error_solo_final_vowel,
-		r_error_final_front_vowel,
-		r_error_double_front_vowel,
-		r_error_sara_e_ae,   // this must come after maybe_sandwich_sara_a
-		r_error_short_o_ang, // this must come after maybe_sandwich_sara_a
-	}
-
 next_input:
 	for i := 0; i < len(input); {
 
@@ -459,12 +539,16 @@ next_input:
 		var length int
 
 		// Run the regexes, in order
-		for _, rule := range rules {
-			matched := rule.ck(&rule, input, i, &length, &c)
+		for r := range s.rules {
+			rule := &s.rules[r]
+			matched := rule.match(input, i, &length, &c)
 			if matched {
 				c.MatchingRule = rule.name
-				/*				fmt.Printf("matched: %s @i=%d length=%d %s\n",
-								rule.name, i, length, c.Repr())*/
+			}
+			if s.tracer != nil {
+				s.trace(rule, input, i, matched, length, &c)
+			}
+			if matched {
 				clusters = append(clusters, c)
 				i += length
 				continue next_input
@@ -476,6 +560,9 @@ next_input:
 		c = makeCluster(input[i : i+1])
 		c.InvalidThai = input[i]
 		c.IsValidThai = false
+		if c.InvalidReason == NoInvalidReason {
+			c.InvalidReason = ReasonUnmatched
+		}
 		clusters = append(clusters, c)
 		i++
 		continue
@@ -681,7 +768,8 @@ var r_maybe_sandwich_sara_a = TccRule{
 }
 
 var r_special_o_ang = TccRule{
-	name: "special_o_ang",
+	name:   "special_o_ang",
+	before: []string{"short_o_ang"},
 	rs: "([:sara e:])" +
 		"(?P<consonant>" +
 		// BEGIN special
@@ -714,7 +802,8 @@ var r_special_o_ang = TccRule{
 }
 
 var r_short_o_ang = TccRule{
-	name: "short_o_ang",
+	name:   "short_o_ang",
+	before: []string{"maybe_sandwich_sara_a"},
 	rs: "([:sara e:])" +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -752,7 +841,8 @@ var r_short_o_ang = TccRule{
 }
 
 var r_sandwich_ia = TccRule{
-	name: "sandwich_ia",
+	name:   "sandwich_ia",
+	before: []string{"maybe_sandwich_sara_a"},
 	rs: "([:sara e:]) " +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -788,7 +878,8 @@ var r_sandwich_ia = TccRule{
 }
 
 var r_sandwich_ueea_er = TccRule{
-	name: "sandwich_ueea_er",
+	name:   "sandwich_ueea_er",
+	before: []string{"maybe_sandwich_sara_a"},
 	rs: "([:sara e:]) " +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -844,7 +935,8 @@ var r_sandwich_ueea_er = TccRule{
 }
 
 var r_sara_ai = TccRule{
-	name: "sara_ai",
+	name:   "sara_ai",
+	before: []string{"sara_ai_single_consonant"},
 	rs: "([:sara ai maimuan:] | [:sara ai maimalai:]) " +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -921,7 +1013,8 @@ var r_sara_ai_single_consonant = TccRule{
 }
 
 var r_medial_er = TccRule{
-	name: "medial_er",
+	name:   "medial_er",
+	before: []string{"maybe_sandwich_sara_a"},
 	rs: "([:sara e:]) " +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -953,7 +1046,8 @@ var r_medial_er = TccRule{
 }
 
 var r_sandwich_ao = TccRule{
-	name: "sandwich_ao",
+	name:   "sandwich_ao",
+	before: []string{"maybe_sandwich_sara_a"},
 	rs: "([:sara e:])" +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -1040,8 +1134,9 @@ var r_single_consonant = TccRule{
 }
 
 var r_sanskrit = TccRule{
-	name: "sanskrit",
-	rs:   "([:ru:]|[:lu:]) [:lakkhangyao:]",
+	name:   "sanskrit",
+	before: []string{"consonant"},
+	rs:     "([:ru:]|[:lu:]) [:lakkhangyao:]",
 	ck: func(s *TccRule, input []GraphemeStack, i int, length *int, c *GStackCluster) bool {
 		m := s.regex.MatchAt(input, i)
 		if !m.Success {
@@ -1088,8 +1183,9 @@ var r_error_solo_diacritic = TccRule{
 }
 
 var r_error_sara_e_ae = TccRule{
-	name: "error_sara_e_ae",
-	rs:   "([:sara e:]|[:sara ae:]) [:consonant: && :diacritic vowel:]",
+	name:  "error_sara_e_ae",
+	after: []string{"maybe_sandwich_sara_a"},
+	rs:    "([:sara e:]|[:sara ae:]) [:consonant: && :diacritic vowel:]",
 	ck: func(s *TccRule, input []GraphemeStack, i int, length *int, c *GStackCluster) bool {
 		m := s.regex.MatchAt(input, i)
 		if !m.Success {
@@ -1159,7 +1255,8 @@ var r_error_double_front_vowel = TccRule{
 
 // some people mispell it with a sara ae at the beginning
 var r_error_short_o_ang = TccRule{
-	name: "error_short_o_ang",
+	name:  "error_short_o_ang",
+	after: []string{"maybe_sandwich_sara_a"},
 	rs: "([:sara ae:])" +
 		"(?P<consonant>" +
 		// BEGIN possible consonants allowed between sandwich vowels
@@ -1,6 +1,8 @@
 package paasaathai
 
 import (
+	"sync"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -903,3 +905,64 @@ func (s *MySuite) TestCluster02(c *C) {
 	c.Check(gcs[0].Tail[0].Main, Equals, THAI_CHARACTER_O_ANG)
 }
 */
+
+func (s *MySuite) TestClusterOffsets(c *C) {
+	input := "a เด็ก"
+	var gcp GStackClusterParser
+	gcp.Initialize()
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks(input))
+	c.Assert(len(clusters), Equals, 4)
+
+	c.Check(clusters[2].Text, Equals, "เด็")
+	c.Check(clusters[2].StartByte, Equals, 2)
+	c.Check(clusters[2].EndByte, Equals, 11)
+	c.Check(clusters[2].StartRune, Equals, 2)
+	c.Check(clusters[2].EndRune, Equals, 5)
+	c.Check(clusters[3].StartByte, Equals, 11)
+	c.Check(clusters[3].EndByte, Equals, len(input))
+}
+
+func (s *MySuite) TestSharedClusterParser(c *C) {
+	c.Check(NewGStackClusterParser(), Equals, NewGStackClusterParser())
+
+	// Parsers compile their own copies of the rules
+	c.Check(r_sara_a_aa.regex, IsNil)
+}
+
+// Run with -race to check that parsers don't share state
+func (s *MySuite) TestClusterParserConcurrency(c *C) {
+	input := ParseGraphemeStacks("ประเทศไทยเเละกรุงเทพ")
+	expected := NewGStackClusterParser().ParseGraphemeStacks(input)
+
+	var wg sync.WaitGroup
+	results := make([][]GStackCluster, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				results[i] = NewGStackClusterParser().ParseGraphemeStacks(input)
+			} else {
+				var gcp GStackClusterParser
+				gcp.Initialize()
+				results[i] = gcp.ParseGraphemeStacks(input)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, clusters := range results {
+		c.Check(clusters, DeepEquals, expected)
+	}
+}
+
+func (s *MySuite) TestInitializeOnce(c *C) {
+	gcp := NewGStackClusterParser()
+	rules := gcp.rules
+	c.Check(gcp.Initialize(), Equals, ParserAlreadyInitializedError)
+	c.Check(&gcp.rules[0], Equals, &rules[0])
+	c.Check(gcp.rules[0].regex, Equals, rules[0].regex)
+
+	var own GStackClusterParser
+	c.Assert(own.Initialize(), IsNil)
+	c.Check(own.Initialize(), Equals, ParserAlreadyInitializedError)
+}
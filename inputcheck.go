@@ -0,0 +1,275 @@
+package paasaathai
+
+// Input sequence checking, for Thai input methods, following WTT 2.0
+// (TIS 1566-2541). Each rune typed is checked against the text before
+// it, so that a keyboard cannot make a stack that the
+// GraphemeStackParser would not read as one, like a tone mark after a
+// front vowel, or two upper vowels on one consonant.
+
+import (
+	"fmt"
+	"unicode"
+)
+
+type InputCheckMode int
+
+const (
+	// Accept every rune
+	InputPassthrough InputCheckMode = 0
+
+	// Reject the runes which would not stack on the text before them
+	InputBasicCheck InputCheckMode = 1
+
+	// Also reject the sequences which WTT 2.0 rejects in its strict
+	// mode, like a space after sara e, or a vowel on ฤ
+	InputStrictCheck InputCheckMode = 2
+)
+
+func (s InputCheckMode) String() string {
+	switch s {
+	case InputPassthrough:
+		return "Passthrough"
+	case InputBasicCheck:
+		return "BasicCheck"
+	case InputStrictCheck:
+		return "StrictCheck"
+	default:
+		return fmt.Sprintf("InputCheckMode(%d)", int(s))
+	}
+}
+
+type InputAction int
+
+const (
+	InputAccept  InputAction = 0
+	InputReject  InputAction = 1
+	InputReplace InputAction = 2
+)
+
+func (s InputAction) String() string {
+	switch s {
+	case InputAccept:
+		return "Accept"
+	case InputReject:
+		return "Reject"
+	case InputReplace:
+		return "Replace"
+	default:
+		return fmt.Sprintf("InputAction(%d)", int(s))
+	}
+}
+
+// What an input method should do with a rune
+type InputResult struct {
+	Action InputAction
+
+	// For InputReplace, the number of runes to delete from the end of
+	// the text, and the text to put in their place, which includes the
+	// rune typed
+	Delete int
+	Insert string
+}
+
+// Checks the runes typed into a Thai input method
+type InputChecker struct {
+	Mode InputCheckMode
+}
+
+// Check the candidate rune, typed after the preceding text.
+//
+// A rune which is rejected can often be fixed, by replacing the stack
+// before it: a tone mark typed before the upper or lower vowel, as in
+// ก่ then ิ, is moved after it, to กิ่. A second tone mark or sign, or a
+// second upper or lower vowel, takes the place of the first, as in ก่
+// then ้, to ก้. And sara e typed twice is sara ae, as the parser reads
+// it.
+func (s *InputChecker) Check(preceding string, candidate rune) InputResult {
+	if s.Mode == InputPassthrough {
+		return InputResult{Action: InputAccept}
+	}
+
+	// A stack is at most graphemeStackLookahead runes, so only the end
+	// of the preceding text matters
+	tail := []rune(preceding)
+	if len(tail) > graphemeStackLookahead {
+		tail = tail[len(tail)-graphemeStackLookahead:]
+	}
+	before := ParseGraphemeStacks(string(tail))
+	after := ParseGraphemeStacks(string(tail) + string(candidate))
+
+	var last GraphemeStack
+	prevClass := wttCTRL
+	if len(before) > 0 {
+		last = before[len(before)-1]
+		prevClass = wttClassOf(tail[len(tail)-1])
+	}
+	class := wttClassOf(candidate)
+	op := wttInputOps[prevClass][class]
+
+	// Did the candidate join the last stack?
+	if len(before) > 0 && len(after) == len(before) {
+		joined := after[len(after)-1]
+		if joined.Text != last.Text+string(candidate) {
+			return InputResult{
+				Action: InputReplace,
+				Delete: last.EndRune - last.StartRune,
+				Insert: joined.Text,
+			}
+		}
+		if s.Mode == InputStrictCheck && op != 'C' {
+			return InputResult{Action: InputReject}
+		}
+		return InputResult{Action: InputAccept}
+	}
+
+	if after[len(after)-1].Err() == nil {
+		// It starts a new stack
+		if s.Mode == InputStrictCheck && op == 'S' {
+			return InputResult{Action: InputReject}
+		}
+		return InputResult{Action: InputAccept}
+	}
+
+	if fixed, ok := s.correct(last, candidate); ok {
+		return InputResult{
+			Action: InputReplace,
+			Delete: last.EndRune - last.StartRune,
+			Insert: fixed,
+		}
+	}
+	return InputResult{Action: InputReject}
+}
+
+// Put the candidate, which did not stack, into the last stack, in the
+// place of a diacritic of the same kind, or before a tone mark or sign
+func (s *InputChecker) correct(last GraphemeStack, candidate rune) (string, bool) {
+	if !RuneIsConsonant(last.Main) {
+		return "", false
+	}
+	runes := []rune{last.Main, last.DiacriticVowel, last.UpperDiacritic}
+	switch {
+	case RuneIsToneMark(candidate) || RuneIsUpperPositionSign(candidate):
+		if last.UpperDiacritic == 0 {
+			return "", false
+		}
+		runes[2] = candidate
+	case RuneIsUpperPosition(candidate) || RuneIsLowerPositionVowel(candidate):
+		if last.DiacriticVowel == 0 && last.UpperDiacritic == 0 {
+			return "", false
+		}
+		runes[1] = candidate
+	default:
+		return "", false
+	}
+
+	stacked := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if r != 0 {
+			stacked = append(stacked, r)
+		}
+	}
+	text := string(stacked)
+	if len(ParseGraphemeStacks(text)) != 1 {
+		return "", false
+	}
+	if s.Mode == InputStrictCheck {
+		for i := 1; i < len(stacked); i++ {
+			if wttInputOps[wttClassOf(stacked[i-1])][wttClassOf(stacked[i])] != 'C' {
+				return "", false
+			}
+		}
+	}
+	return text, true
+}
+
+// The classes of characters in WTT 2.0
+type wttClass int
+
+const (
+	wttCTRL wttClass = iota
+	wttNON
+	wttCONS
+	wttLV
+	wttFV1
+	wttFV2
+	wttFV3
+	wttBV1
+	wttBV2
+	wttBD
+	wttTONE
+	wttAD1
+	wttAD2
+	wttAD3
+	wttAV1
+	wttAV2
+	wttAV3
+)
+
+func wttClassOf(r rune) wttClass {
+	switch {
+	case unicode.IsControl(r):
+		return wttCTRL
+	case r == THAI_CHARACTER_RU || r == THAI_CHARACTER_LU:
+		return wttFV3
+	case RuneIsConsonant(r):
+		return wttCONS
+	case RuneIsFrontPositionVowel(r):
+		return wttLV
+	case RuneIsToneMark(r):
+		return wttTONE
+	}
+	switch r {
+	case THAI_CHARACTER_SARA_A, THAI_CHARACTER_SARA_AA, THAI_CHARACTER_SARA_AM:
+		return wttFV1
+	case THAI_CHARACTER_LAKKHANGYAO:
+		return wttFV2
+	case THAI_CHARACTER_SARA_U:
+		return wttBV1
+	case THAI_CHARACTER_SARA_UU:
+		return wttBV2
+	case THAI_CHARACTER_PHINTHU:
+		return wttBD
+	case THAI_CHARACTER_THANTHAKHAT, THAI_CHARACTER_NIKHAHIT:
+		return wttAD1
+	case THAI_CHARACTER_MAITAIKHU:
+		return wttAD2
+	case THAI_CHARACTER_YAMAKKAN:
+		return wttAD3
+	case THAI_CHARACTER_SARA_I:
+		return wttAV1
+	case THAI_CHARACTER_MAI_HAN_AKAT, THAI_CHARACTER_SARA_UE:
+		return wttAV2
+	case THAI_CHARACTER_SARA_II, THAI_CHARACTER_SARA_UEE:
+		return wttAV3
+	}
+	return wttNON
+}
+
+// The WTT 2.0 input sequence table. The row is the class of the rune
+// before, and the column is the class of the rune typed.
+//
+//	A: accept
+//	C: compose, as part of the stack before
+//	S: accept, except in strict mode
+//	R: reject
+//	X: not part of the text
+var wttInputOps = [...]string{
+	// CTRL NON CONS LV FV1 FV2 FV3 BV1 BV2 BD TONE AD1 AD2 AD3 AV1 AV2 AV3
+	wttCTRL: "XAAAAAARRRRRRRRRR",
+	wttNON:  "XAAASSARRRRRRRRRR",
+	wttCONS: "XAAAASACCCCCCCCCC",
+	wttLV:   "XSASSSSRRRRRRRRRR",
+	wttFV1:  "XSASASARRRRRRRRRR",
+	wttFV2:  "XAAAASARRRRRRRRRR",
+	wttFV3:  "XAAASASRRRRRRRRRR",
+	wttBV1:  "XAAASSARRRCCRRRRR",
+	wttBV2:  "XAAASSARRRCRRRRRR",
+	wttBD:   "XAAASSARRRRRRRRRR",
+	wttTONE: "XAAAAAARRRRRRRRRR",
+	wttAD1:  "XAAASSARRRRRRRRRR",
+	wttAD2:  "XAAASSARRRRRRRRRR",
+	wttAD3:  "XAAASSARRRRRRRRRR",
+	wttAV1:  "XAAASSARRRCCRRRRR",
+	wttAV2:  "XAAASSARRRCRRRRRR",
+	wttAV3:  "XAAASSARRRCRCRRRR",
+}
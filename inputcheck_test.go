@@ -0,0 +1,89 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestInputCheckerBasic(c *C) {
+	checker := &InputChecker{Mode: InputBasicCheck}
+	accept := InputResult{Action: InputAccept}
+	reject := InputResult{Action: InputReject}
+
+	c.Check(checker.Check("", THAI_CHARACTER_KO_KAI), Equals, accept)
+	c.Check(checker.Check("ก", THAI_CHARACTER_SARA_I), Equals, accept)
+	c.Check(checker.Check("กิ", THAI_CHARACTER_MAI_EK), Equals, accept)
+	c.Check(checker.Check("สวัสด", THAI_CHARACTER_SARA_II), Equals, accept)
+
+	// A tone mark may not follow a front vowel, or begin the text
+	c.Check(checker.Check("เ", THAI_CHARACTER_MAI_EK), Equals, reject)
+	c.Check(checker.Check("", THAI_CHARACTER_MAI_EK), Equals, reject)
+	c.Check(checker.Check("a", THAI_CHARACTER_SARA_I), Equals, reject)
+
+	// A second upper vowel takes the place of the first
+	c.Check(checker.Check("กิ", THAI_CHARACTER_SARA_II), Equals,
+		InputResult{Action: InputReplace, Delete: 2, Insert: "กี"})
+	// The vowel goes under the tone mark
+	c.Check(checker.Check("ก่", THAI_CHARACTER_SARA_I), Equals,
+		InputResult{Action: InputReplace, Delete: 2, Insert: "กิ่"})
+	c.Check(checker.Check("ก่", THAI_CHARACTER_SARA_U), Equals,
+		InputResult{Action: InputReplace, Delete: 2, Insert: "กุ่"})
+	// A second tone mark takes the place of the first
+	c.Check(checker.Check("xกิ่", THAI_CHARACTER_MAI_THO), Equals,
+		InputResult{Action: InputReplace, Delete: 3, Insert: "กิ้"})
+	// Sara e twice is sara ae
+	c.Check(checker.Check("เ", THAI_CHARACTER_SARA_E), Equals,
+		InputResult{Action: InputReplace, Delete: 1, Insert: "แ"})
+
+	// Non-combining runes always start a stack
+	c.Check(checker.Check("เ", ' '), Equals, accept)
+	c.Check(checker.Check("ฤ", THAI_CHARACTER_SARA_I), Equals, accept)
+}
+
+func (s *MySuite) TestInputCheckerStrict(c *C) {
+	checker := &InputChecker{Mode: InputStrictCheck}
+	accept := InputResult{Action: InputAccept}
+	reject := InputResult{Action: InputReject}
+
+	c.Check(checker.Check("เ", THAI_CHARACTER_KO_KAI), Equals, accept)
+	c.Check(checker.Check("กิ", THAI_CHARACTER_MAI_EK), Equals, accept)
+	c.Check(checker.Check("เ", ' '), Equals, reject)
+	c.Check(checker.Check("ฤ", THAI_CHARACTER_SARA_I), Equals, reject)
+	// The parser stacks a maitaikhu on sara u, but WTT 2.0 does not
+	c.Check(checker.Check("กุ", THAI_CHARACTER_MAITAIKHU), Equals, reject)
+	c.Check(checker.Check("ก่", THAI_CHARACTER_SARA_I), Equals,
+		InputResult{Action: InputReplace, Delete: 2, Insert: "กิ่"})
+}
+
+func (s *MySuite) TestInputCheckerPassthrough(c *C) {
+	checker := &InputChecker{}
+	c.Check(checker.Check("เ", THAI_CHARACTER_MAI_EK), Equals,
+		InputResult{Action: InputAccept})
+}
+
+// What the basic check accepts, the parser reads as valid stacks
+func (s *MySuite) TestInputCheckerMatchesParser(c *C) {
+	checker := &InputChecker{Mode: InputBasicCheck}
+	for _, first := range []rune{'a', THAI_CHARACTER_KO_KAI, THAI_CHARACTER_SARA_E} {
+		for second := rune(0x0e01); second <= 0x0e5b; second++ {
+			for third := rune(0x0e01); third <= 0x0e5b; third++ {
+				text := string([]rune{first, second})
+				result := checker.Check(text, third)
+				switch result.Action {
+				case InputAccept:
+					text += string(third)
+				case InputReplace:
+					runes := []rune(text)
+					text = string(runes[:len(runes)-result.Delete]) + result.Insert
+				default:
+					continue
+				}
+				if checker.Check(string(first), second).Action != InputAccept {
+					continue
+				}
+				for _, gs := range ParseGraphemeStacks(text) {
+					c.Check(gs.Err(), IsNil, Commentf("%s", text))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package paasaathai
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The JSON of a code point. The name is only for people, and is not
+// there for code points that are not Thai.
+type jsonRune struct {
+	CodePoint rune   `json:"code_point"`
+	Name      string `json:"name,omitempty"`
+}
+
+func newJSONRune(r rune) *jsonRune {
+	if r == 0 {
+		return nil
+	}
+	return &jsonRune{
+		CodePoint: r,
+		Name:      RuneToName(r),
+	}
+}
+
+func (s *jsonRune) rune() rune {
+	if s == nil {
+		return 0
+	}
+	return s.CodePoint
+}
+
+type graphemeStackJSON struct {
+	Text           string    `json:"text"`
+	Main           *jsonRune `json:"main,omitempty"`
+	DiacriticVowel *jsonRune `json:"diacritic_vowel,omitempty"`
+	UpperDiacritic *jsonRune `json:"upper_diacritic,omitempty"`
+	StartByte      int       `json:"start_byte"`
+	EndByte        int       `json:"end_byte"`
+	StartRune      int       `json:"start_rune"`
+	EndRune        int       `json:"end_rune"`
+}
+
+// Implements the json.Marshaler interface. The code points which are
+// not set are left out, and each one that is has its name, as in
+//
+//	{"text":"กี่","main":{"code_point":3585,"name":"THAI_CHARACTER_KO_KAI"},...}
+func (s GraphemeStack) MarshalJSON() ([]byte, error) {
+	return json.Marshal(graphemeStackJSON{
+		Text:           s.Text,
+		Main:           newJSONRune(s.Main),
+		DiacriticVowel: newJSONRune(s.DiacriticVowel),
+		UpperDiacritic: newJSONRune(s.UpperDiacritic),
+		StartByte:      s.StartByte,
+		EndByte:        s.EndByte,
+		StartRune:      s.StartRune,
+		EndRune:        s.EndRune,
+	})
+}
+
+// Implements the json.Unmarshaler interface. The code points are read
+// from their numbers; the names are ignored.
+func (s *GraphemeStack) UnmarshalJSON(data []byte) error {
+	var j graphemeStackJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*s = GraphemeStack{
+		Text:           j.Text,
+		Main:           j.Main.rune(),
+		DiacriticVowel: j.DiacriticVowel.rune(),
+		UpperDiacritic: j.UpperDiacritic.rune(),
+		StartByte:      j.StartByte,
+		EndByte:        j.EndByte,
+		StartRune:      j.StartRune,
+		EndRune:        j.EndRune,
+	}
+	return nil
+}
+
+type gstackClusterJSON struct {
+	Text           string          `json:"text"`
+	IsThai         bool            `json:"is_thai"`
+	IsValidThai    bool            `json:"is_valid_thai"`
+	InvalidReason  *InvalidReason  `json:"invalid_reason,omitempty"`
+	FrontVowel     *GraphemeStack  `json:"front_vowel,omitempty"`
+	FirstConsonant *GraphemeStack  `json:"first_consonant,omitempty"`
+	SingleMidSign  *GraphemeStack  `json:"single_mid_sign,omitempty"`
+	Tail           []GraphemeStack `json:"tail,omitempty"`
+	InvalidThai    *GraphemeStack  `json:"invalid_thai,omitempty"`
+	MatchingRule   string          `json:"matching_rule,omitempty"`
+	StartByte      int             `json:"start_byte"`
+	EndByte        int             `json:"end_byte"`
+	StartRune      int             `json:"start_rune"`
+	EndRune        int             `json:"end_rune"`
+}
+
+// A part of a cluster that is set, or nil
+func presentGraphemeStack(gs GraphemeStack) *GraphemeStack {
+	if gs == (GraphemeStack{}) {
+		return nil
+	}
+	return &gs
+}
+
+// The part of a cluster, or the zero value if it is not set
+func graphemeStackOrZero(gs *GraphemeStack) GraphemeStack {
+	if gs == nil {
+		return GraphemeStack{}
+	}
+	return *gs
+}
+
+// Implements the json.Marshaler interface. The parts of the cluster
+// which are not set are left out, and so is an empty Tail, which is
+// read back as nil.
+func (s GStackCluster) MarshalJSON() ([]byte, error) {
+	j := gstackClusterJSON{
+		Text:           s.Text,
+		IsThai:         s.IsThai,
+		IsValidThai:    s.IsValidThai,
+		FrontVowel:     presentGraphemeStack(s.FrontVowel),
+		FirstConsonant: presentGraphemeStack(s.FirstConsonant),
+		SingleMidSign:  presentGraphemeStack(s.SingleMidSign),
+		Tail:           s.Tail,
+		InvalidThai:    presentGraphemeStack(s.InvalidThai),
+		MatchingRule:   s.MatchingRule,
+		StartByte:      s.StartByte,
+		EndByte:        s.EndByte,
+		StartRune:      s.StartRune,
+		EndRune:        s.EndRune,
+	}
+	if s.InvalidReason != NoInvalidReason {
+		j.InvalidReason = &s.InvalidReason
+	}
+	return json.Marshal(j)
+}
+
+// Implements the json.Unmarshaler interface
+func (s *GStackCluster) UnmarshalJSON(data []byte) error {
+	var j gstackClusterJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*s = GStackCluster{
+		Text:           j.Text,
+		IsThai:         j.IsThai,
+		IsValidThai:    j.IsValidThai,
+		FrontVowel:     graphemeStackOrZero(j.FrontVowel),
+		FirstConsonant: graphemeStackOrZero(j.FirstConsonant),
+		SingleMidSign:  graphemeStackOrZero(j.SingleMidSign),
+		Tail:           j.Tail,
+		InvalidThai:    graphemeStackOrZero(j.InvalidThai),
+		MatchingRule:   j.MatchingRule,
+		StartByte:      j.StartByte,
+		EndByte:        j.EndByte,
+		StartRune:      j.StartRune,
+		EndRune:        j.EndRune,
+	}
+	if j.InvalidReason != nil {
+		s.InvalidReason = *j.InvalidReason
+	}
+	return nil
+}
+
+// Implements the encoding.TextMarshaler interface, so that the reason
+// is its name in JSON
+func (s InvalidReason) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Implements the encoding.TextUnmarshaler interface
+func (s *InvalidReason) UnmarshalText(text []byte) error {
+	for r := NoInvalidReason; r <= ReasonUnmatched; r++ {
+		if r.String() == string(text) {
+			*s = r
+			return nil
+		}
+	}
+	var n int
+	if _, err := fmt.Sscanf(string(text), "InvalidReason(%d)", &n); err == nil {
+		*s = InvalidReason(n)
+		return nil
+	}
+	return fmt.Errorf("unknown InvalidReason %q", text)
+}
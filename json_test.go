@@ -0,0 +1,86 @@
+package paasaathai
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestGraphemeStackJSON(c *C) {
+	gs := ParseGraphemeStacks("aกี่")[1]
+	data, err := json.Marshal(gs)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"text":"กี่",`+
+		`"main":{"code_point":3585,"name":"THAI_CHARACTER_KO_KAI"},`+
+		`"diacritic_vowel":{"code_point":3637,"name":"THAI_CHARACTER_SARA_II"},`+
+		`"upper_diacritic":{"code_point":3656,"name":"THAI_CHARACTER_MAI_EK"},`+
+		`"start_byte":1,"end_byte":10,"start_rune":1,"end_rune":4}`)
+
+	// Non-Thai code points have no names, and parts that are not set
+	// are left out
+	data, err = json.Marshal(ParseGraphemeStacks("a")[0])
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"text":"a","main":{"code_point":97},`+
+		`"start_byte":0,"end_byte":1,"start_rune":0,"end_rune":1}`)
+
+	var back GraphemeStack
+	c.Assert(json.Unmarshal([]byte(`{"text":"กี่",`+
+		`"main":{"code_point":3585,"name":"THAI_CHARACTER_KO_KAI"},`+
+		`"upper_diacritic":{"code_point":3656}}`), &back), IsNil)
+	c.Check(back, Equals, GraphemeStack{
+		Text:           "กี่",
+		Main:           THAI_CHARACTER_KO_KAI,
+		UpperDiacritic: THAI_CHARACTER_MAI_EK,
+	})
+}
+
+func (s *MySuite) TestGStackClusterJSON(c *C) {
+	gcp := NewGStackClusterParser()
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("ไม่"))
+	c.Assert(len(clusters), Equals, 1)
+
+	data, err := json.Marshal(clusters[0])
+	c.Assert(err, IsNil)
+
+	// Only the parts that are set are there
+	var fields map[string]interface{}
+	c.Assert(json.Unmarshal(data, &fields), IsNil)
+	_, has := fields["front_vowel"]
+	c.Check(has, Equals, true)
+	_, has = fields["first_consonant"]
+	c.Check(has, Equals, true)
+	for _, name := range []string{"single_mid_sign", "invalid_thai",
+		"invalid_reason", "tail"} {
+		_, has = fields[name]
+		c.Check(has, Equals, false, Commentf("%s", name))
+	}
+}
+
+func (s *MySuite) TestGStackClusterJSONRoundTrip(c *C) {
+	gcp := NewGStackClusterParser()
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks(
+		"ผู้ใหญ่ a เเละ ุ ๆ กรุงเทพฯ เกาะ ภัทร์ เ"))
+
+	data, err := json.Marshal(clusters)
+	c.Assert(err, IsNil)
+	var back []GStackCluster
+	c.Assert(json.Unmarshal(data, &back), IsNil)
+	c.Check(back, DeepEquals, clusters)
+}
+
+func (s *MySuite) TestInvalidReasonJSON(c *C) {
+	data, err := json.Marshal(ReasonSoloDiacritic)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `"SoloDiacritic"`)
+
+	for r := NoInvalidReason; r <= ReasonUnmatched+1; r++ {
+		data, err = json.Marshal(r)
+		c.Assert(err, IsNil)
+		var back InvalidReason
+		c.Check(json.Unmarshal(data, &back), IsNil)
+		c.Check(back, Equals, r)
+	}
+
+	var back InvalidReason
+	c.Check(json.Unmarshal([]byte(`"NoSuchReason"`), &back), NotNil)
+}
@@ -0,0 +1,362 @@
+package paasaathai
+
+// Thai keyboard layouts, to fix text that was typed with the keyboard in
+// the wrong mode: "l;ylfu" typed in the US layout, when สวัสดี was
+// meant, or ้ำสสน typed in Kedmanee, when hello was meant.
+
+import (
+	"strings"
+	"unicode"
+)
+
+// A key of the keyboard. Index 0 is the character typed without shift,
+// and 1 is the character typed with shift.
+type KeyboardKey struct {
+	// The characters of the key in the US QWERTY layout
+	QWERTY [2]rune
+
+	// The characters of the key in the Thai layout
+	Thai [2]rune
+}
+
+// A Thai keyboard layout
+type KeyboardLayout struct {
+	Name string
+	Keys []KeyboardKey
+
+	fromQWERTY map[rune]rune
+	toQWERTY   map[rune]rune
+}
+
+// Make a layout from its keys. If a character is on more than one key,
+// the first key is used to convert it back to QWERTY.
+func NewKeyboardLayout(name string, keys []KeyboardKey) *KeyboardLayout {
+	s := &KeyboardLayout{
+		Name:       name,
+		Keys:       keys,
+		fromQWERTY: make(map[rune]rune, len(keys)*2),
+		toQWERTY:   make(map[rune]rune, len(keys)*2),
+	}
+	for _, key := range keys {
+		for level := 0; level < 2; level++ {
+			q, t := key.QWERTY[level], key.Thai[level]
+			if _, has := s.fromQWERTY[q]; !has {
+				s.fromQWERTY[q] = t
+			}
+			if _, has := s.toQWERTY[t]; !has {
+				s.toQWERTY[t] = q
+			}
+		}
+	}
+	return s
+}
+
+// What the text would be if the same keys had been pressed in this
+// layout. Characters which are not on the keyboard are kept.
+func (s *KeyboardLayout) FromQWERTY(text string) string {
+	return mapRunes(text, s.fromQWERTY)
+}
+
+// What the text would be if the same keys had been pressed in the US
+// QWERTY layout. Characters which are not on the keyboard are kept.
+func (s *KeyboardLayout) ToQWERTY(text string) string {
+	return mapRunes(text, s.toQWERTY)
+}
+
+func mapRunes(text string, table map[rune]rune) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		if m, has := table[r]; has {
+			runes[i] = m
+		}
+	}
+	return string(runes)
+}
+
+// The Kedmanee layout, which is the usual one, as in TIS 820-2538
+var Kedmanee = NewKeyboardLayout("Kedmanee", []KeyboardKey{
+	{QWERTY: [2]rune{'`', '~'}, Thai: [2]rune{'_', '%'}},
+	{QWERTY: [2]rune{'1', '!'}, Thai: [2]rune{THAI_CHARACTER_LAKKHANGYAO, '+'}},
+	{QWERTY: [2]rune{'2', '@'}, Thai: [2]rune{'/', THAI_DIGIT_ONE}},
+	{QWERTY: [2]rune{'3', '#'}, Thai: [2]rune{'-', THAI_DIGIT_TWO}},
+	{QWERTY: [2]rune{'4', '$'}, Thai: [2]rune{THAI_CHARACTER_PHO_SAMPHAO, THAI_DIGIT_THREE}},
+	{QWERTY: [2]rune{'5', '%'}, Thai: [2]rune{THAI_CHARACTER_THO_THUNG, THAI_DIGIT_FOUR}},
+	{QWERTY: [2]rune{'6', '^'}, Thai: [2]rune{THAI_CHARACTER_SARA_U, THAI_CHARACTER_SARA_UU}},
+	{QWERTY: [2]rune{'7', '&'}, Thai: [2]rune{THAI_CHARACTER_SARA_UE, THAI_CURRENCY_SYMBOL_BAHT}},
+	{QWERTY: [2]rune{'8', '*'}, Thai: [2]rune{THAI_CHARACTER_KHO_KHWAI, THAI_DIGIT_FIVE}},
+	{QWERTY: [2]rune{'9', '('}, Thai: [2]rune{THAI_CHARACTER_TO_TAO, THAI_DIGIT_SIX}},
+	{QWERTY: [2]rune{'0', ')'}, Thai: [2]rune{THAI_CHARACTER_CHO_CHAN, THAI_DIGIT_SEVEN}},
+	{QWERTY: [2]rune{'-', '_'}, Thai: [2]rune{THAI_CHARACTER_KHO_KHAI, THAI_DIGIT_EIGHT}},
+	{QWERTY: [2]rune{'=', '+'}, Thai: [2]rune{THAI_CHARACTER_CHO_CHANG, THAI_DIGIT_NINE}},
+	{QWERTY: [2]rune{'q', 'Q'}, Thai: [2]rune{THAI_CHARACTER_MAIYAMOK, THAI_DIGIT_ZERO}},
+	{QWERTY: [2]rune{'w', 'W'}, Thai: [2]rune{THAI_CHARACTER_SARA_AI_MAIMALAI, '"'}},
+	{QWERTY: [2]rune{'e', 'E'}, Thai: [2]rune{THAI_CHARACTER_SARA_AM, THAI_CHARACTER_DO_CHADA}},
+	{QWERTY: [2]rune{'r', 'R'}, Thai: [2]rune{THAI_CHARACTER_PHO_PHAN, THAI_CHARACTER_THO_NANGMONTHO}},
+	{QWERTY: [2]rune{'t', 'T'}, Thai: [2]rune{THAI_CHARACTER_SARA_A, THAI_CHARACTER_THO_THONG}},
+	{QWERTY: [2]rune{'y', 'Y'}, Thai: [2]rune{THAI_CHARACTER_MAI_HAN_AKAT, THAI_CHARACTER_NIKHAHIT}},
+	{QWERTY: [2]rune{'u', 'U'}, Thai: [2]rune{THAI_CHARACTER_SARA_II, THAI_CHARACTER_MAI_TRI}},
+	{QWERTY: [2]rune{'i', 'I'}, Thai: [2]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_NO_NEN}},
+	{QWERTY: [2]rune{'o', 'O'}, Thai: [2]rune{THAI_CHARACTER_NO_NU, THAI_CHARACTER_PAIYANNOI}},
+	{QWERTY: [2]rune{'p', 'P'}, Thai: [2]rune{THAI_CHARACTER_YO_YAK, THAI_CHARACTER_YO_YING}},
+	{QWERTY: [2]rune{'[', '{'}, Thai: [2]rune{THAI_CHARACTER_BO_BAIMAI, THAI_CHARACTER_THO_THAN}},
+	{QWERTY: [2]rune{']', '}'}, Thai: [2]rune{THAI_CHARACTER_LO_LING, ','}},
+	{QWERTY: [2]rune{'a', 'A'}, Thai: [2]rune{THAI_CHARACTER_FO_FAN, THAI_CHARACTER_RU}},
+	{QWERTY: [2]rune{'s', 'S'}, Thai: [2]rune{THAI_CHARACTER_HO_HIP, THAI_CHARACTER_KHO_RAKHANG}},
+	{QWERTY: [2]rune{'d', 'D'}, Thai: [2]rune{THAI_CHARACTER_KO_KAI, THAI_CHARACTER_TO_PATAK}},
+	{QWERTY: [2]rune{'f', 'F'}, Thai: [2]rune{THAI_CHARACTER_DO_DEK, THAI_CHARACTER_SARA_O}},
+	{QWERTY: [2]rune{'g', 'G'}, Thai: [2]rune{THAI_CHARACTER_SARA_E, THAI_CHARACTER_CHO_CHOE}},
+	{QWERTY: [2]rune{'h', 'H'}, Thai: [2]rune{THAI_CHARACTER_MAI_THO, THAI_CHARACTER_MAITAIKHU}},
+	{QWERTY: [2]rune{'j', 'J'}, Thai: [2]rune{THAI_CHARACTER_MAI_EK, THAI_CHARACTER_MAI_CHATTAWA}},
+	{QWERTY: [2]rune{'k', 'K'}, Thai: [2]rune{THAI_CHARACTER_SARA_AA, THAI_CHARACTER_SO_RUSI}},
+	{QWERTY: [2]rune{'l', 'L'}, Thai: [2]rune{THAI_CHARACTER_SO_SUA, THAI_CHARACTER_SO_SALA}},
+	{QWERTY: [2]rune{';', ':'}, Thai: [2]rune{THAI_CHARACTER_WO_WAEN, THAI_CHARACTER_SO_SO}},
+	{QWERTY: [2]rune{'\'', '"'}, Thai: [2]rune{THAI_CHARACTER_NGO_NGU, '.'}},
+	{QWERTY: [2]rune{'z', 'Z'}, Thai: [2]rune{THAI_CHARACTER_PHO_PHUNG, '('}},
+	{QWERTY: [2]rune{'x', 'X'}, Thai: [2]rune{THAI_CHARACTER_PO_PLA, ')'}},
+	{QWERTY: [2]rune{'c', 'C'}, Thai: [2]rune{THAI_CHARACTER_SARA_AE, THAI_CHARACTER_CHO_CHING}},
+	{QWERTY: [2]rune{'v', 'V'}, Thai: [2]rune{THAI_CHARACTER_O_ANG, THAI_CHARACTER_HO_NOKHUK}},
+	{QWERTY: [2]rune{'b', 'B'}, Thai: [2]rune{THAI_CHARACTER_SARA_I, THAI_CHARACTER_PHINTHU}},
+	{QWERTY: [2]rune{'n', 'N'}, Thai: [2]rune{THAI_CHARACTER_SARA_UEE, THAI_CHARACTER_THANTHAKHAT}},
+	{QWERTY: [2]rune{'m', 'M'}, Thai: [2]rune{THAI_CHARACTER_THO_THAHAN, '?'}},
+	{QWERTY: [2]rune{',', '<'}, Thai: [2]rune{THAI_CHARACTER_MO_MA, THAI_CHARACTER_THO_PHUTHAO}},
+	{QWERTY: [2]rune{'.', '>'}, Thai: [2]rune{THAI_CHARACTER_SARA_AI_MAIMUAN, THAI_CHARACTER_LO_CHULA}},
+	{QWERTY: [2]rune{'/', '?'}, Thai: [2]rune{THAI_CHARACTER_FO_FA, THAI_CHARACTER_LU}},
+	{QWERTY: [2]rune{'\\', '|'}, Thai: [2]rune{THAI_CHARACTER_KHO_KHUAT, THAI_CHARACTER_KHO_KHON}},
+})
+
+// The Pattachote layout
+var Pattachote = NewKeyboardLayout("Pattachote", []KeyboardKey{
+	{QWERTY: [2]rune{'`', '~'}, Thai: [2]rune{'_', THAI_CURRENCY_SYMBOL_BAHT}},
+	{QWERTY: [2]rune{'1', '!'}, Thai: [2]rune{'=', '+'}},
+	{QWERTY: [2]rune{'2', '@'}, Thai: [2]rune{THAI_DIGIT_TWO, '"'}},
+	{QWERTY: [2]rune{'3', '#'}, Thai: [2]rune{THAI_DIGIT_THREE, '/'}},
+	{QWERTY: [2]rune{'4', '$'}, Thai: [2]rune{THAI_DIGIT_FOUR, ','}},
+	{QWERTY: [2]rune{'5', '%'}, Thai: [2]rune{THAI_DIGIT_FIVE, '?'}},
+	{QWERTY: [2]rune{'6', '^'}, Thai: [2]rune{THAI_CHARACTER_SARA_UU, THAI_CHARACTER_SARA_U}},
+	{QWERTY: [2]rune{'7', '&'}, Thai: [2]rune{THAI_DIGIT_SEVEN, '_'}},
+	{QWERTY: [2]rune{'8', '*'}, Thai: [2]rune{THAI_DIGIT_EIGHT, '.'}},
+	{QWERTY: [2]rune{'9', '('}, Thai: [2]rune{THAI_DIGIT_NINE, '('}},
+	{QWERTY: [2]rune{'0', ')'}, Thai: [2]rune{THAI_DIGIT_ZERO, ')'}},
+	{QWERTY: [2]rune{'-', '_'}, Thai: [2]rune{THAI_DIGIT_ONE, '-'}},
+	{QWERTY: [2]rune{'=', '+'}, Thai: [2]rune{THAI_DIGIT_SIX, '%'}},
+	{QWERTY: [2]rune{'q', 'Q'}, Thai: [2]rune{THAI_CHARACTER_MAITAIKHU, THAI_CHARACTER_MAI_TRI}},
+	{QWERTY: [2]rune{'w', 'W'}, Thai: [2]rune{THAI_CHARACTER_TO_TAO, THAI_CHARACTER_RU}},
+	{QWERTY: [2]rune{'e', 'E'}, Thai: [2]rune{THAI_CHARACTER_YO_YAK, THAI_CHARACTER_MAIYAMOK}},
+	{QWERTY: [2]rune{'r', 'R'}, Thai: [2]rune{THAI_CHARACTER_O_ANG, THAI_CHARACTER_YO_YING}},
+	{QWERTY: [2]rune{'t', 'T'}, Thai: [2]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_SO_RUSI}},
+	{QWERTY: [2]rune{'y', 'Y'}, Thai: [2]rune{THAI_CHARACTER_MAI_EK, THAI_CHARACTER_SARA_UE}},
+	{QWERTY: [2]rune{'u', 'U'}, Thai: [2]rune{THAI_CHARACTER_DO_DEK, THAI_CHARACTER_FO_FA}},
+	{QWERTY: [2]rune{'i', 'I'}, Thai: [2]rune{THAI_CHARACTER_MO_MA, THAI_CHARACTER_SO_SO}},
+	{QWERTY: [2]rune{'o', 'O'}, Thai: [2]rune{THAI_CHARACTER_WO_WAEN, THAI_CHARACTER_THO_THUNG}},
+	{QWERTY: [2]rune{'p', 'P'}, Thai: [2]rune{THAI_CHARACTER_SARA_AE, THAI_CHARACTER_THO_PHUTHAO}},
+	{QWERTY: [2]rune{'[', '{'}, Thai: [2]rune{THAI_CHARACTER_SARA_AI_MAIMUAN, THAI_CHARACTER_PAIYANNOI}},
+	{QWERTY: [2]rune{']', '}'}, Thai: [2]rune{THAI_CHARACTER_CHO_CHOE, THAI_CHARACTER_LU}},
+	{QWERTY: [2]rune{'a', 'A'}, Thai: [2]rune{THAI_CHARACTER_MAI_THO, THAI_CHARACTER_MAI_CHATTAWA}},
+	{QWERTY: [2]rune{'s', 'S'}, Thai: [2]rune{THAI_CHARACTER_THO_THAHAN, THAI_CHARACTER_THO_THONG}},
+	{QWERTY: [2]rune{'d', 'D'}, Thai: [2]rune{THAI_CHARACTER_NGO_NGU, THAI_CHARACTER_SARA_AM}},
+	{QWERTY: [2]rune{'f', 'F'}, Thai: [2]rune{THAI_CHARACTER_KO_KAI, THAI_CHARACTER_NO_NEN}},
+	{QWERTY: [2]rune{'g', 'G'}, Thai: [2]rune{THAI_CHARACTER_MAI_HAN_AKAT, THAI_CHARACTER_THANTHAKHAT}},
+	{QWERTY: [2]rune{'h', 'H'}, Thai: [2]rune{THAI_CHARACTER_SARA_II, THAI_CHARACTER_SARA_UEE}},
+	{QWERTY: [2]rune{'j', 'J'}, Thai: [2]rune{THAI_CHARACTER_SARA_AA, THAI_CHARACTER_PHO_PHUNG}},
+	{QWERTY: [2]rune{'k', 'K'}, Thai: [2]rune{THAI_CHARACTER_NO_NU, THAI_CHARACTER_CHO_CHANG}},
+	{QWERTY: [2]rune{'l', 'L'}, Thai: [2]rune{THAI_CHARACTER_SARA_E, THAI_CHARACTER_SARA_O}},
+	{QWERTY: [2]rune{';', ':'}, Thai: [2]rune{THAI_CHARACTER_SARA_AI_MAIMALAI, THAI_CHARACTER_KHO_RAKHANG}},
+	{QWERTY: [2]rune{'\'', '"'}, Thai: [2]rune{THAI_CHARACTER_KHO_KHAI, THAI_CHARACTER_THO_NANGMONTHO}},
+	{QWERTY: [2]rune{'z', 'Z'}, Thai: [2]rune{THAI_CHARACTER_BO_BAIMAI, THAI_CHARACTER_DO_CHADA}},
+	{QWERTY: [2]rune{'x', 'X'}, Thai: [2]rune{THAI_CHARACTER_PO_PLA, THAI_CHARACTER_TO_PATAK}},
+	{QWERTY: [2]rune{'c', 'C'}, Thai: [2]rune{THAI_CHARACTER_LO_LING, THAI_CHARACTER_THO_THAN}},
+	{QWERTY: [2]rune{'v', 'V'}, Thai: [2]rune{THAI_CHARACTER_HO_HIP, THAI_CHARACTER_PHO_SAMPHAO}},
+	{QWERTY: [2]rune{'b', 'B'}, Thai: [2]rune{THAI_CHARACTER_SARA_I, THAI_CHARACTER_PHINTHU}},
+	{QWERTY: [2]rune{'n', 'N'}, Thai: [2]rune{THAI_CHARACTER_KHO_KHWAI, THAI_CHARACTER_SO_SALA}},
+	{QWERTY: [2]rune{'m', 'M'}, Thai: [2]rune{THAI_CHARACTER_SO_SUA, THAI_CHARACTER_HO_NOKHUK}},
+	{QWERTY: [2]rune{',', '<'}, Thai: [2]rune{THAI_CHARACTER_SARA_A, THAI_CHARACTER_FO_FAN}},
+	{QWERTY: [2]rune{'.', '>'}, Thai: [2]rune{THAI_CHARACTER_CHO_CHAN, THAI_CHARACTER_CHO_CHING}},
+	{QWERTY: [2]rune{'/', '?'}, Thai: [2]rune{THAI_CHARACTER_PHO_PHAN, THAI_CHARACTER_LO_CHULA}},
+	{QWERTY: [2]rune{'\\', '|'}, Thai: [2]rune{THAI_CHARACTER_LAKKHANGYAO, THAI_CHARACTER_NIKHAHIT}},
+})
+
+// Decides whether Latin text is Thai that was typed with the keyboard
+// in the US layout, and whether Thai text is Latin text typed with the
+// keyboard in a Thai layout, as in a search box that corrects it
+type LayoutDetector struct {
+	// The layouts to try, in order. If empty, Kedmanee and Pattachote
+	// are tried.
+	Layouts []*KeyboardLayout
+
+	// If nil, NewGStackClusterParser is used
+	Parser *GStackClusterParser
+
+	// If set, more than half of the Thai clusters of the converted text
+	// must be in dictionary words. Otherwise, it is enough that they
+	// are all valid, which many English words are too, so set this to
+	// correct text automatically.
+	Dictionary *Dictionary
+
+	// If set, more than half of the words of the text converted by
+	// MistypedLatin must be in it, in lower case. Otherwise, it is
+	// enough that every word has a vowel.
+	LatinWords *Dictionary
+}
+
+// If the text looks like Thai typed in the US layout, return the Thai
+// text and the layout it was meant for. Text which has Thai in it
+// already, or no Latin letters, is never mistyped.
+func (s *LayoutDetector) MistypedThai(text string) (string, *KeyboardLayout, bool) {
+	hasLetter := false
+	for _, r := range text {
+		if RuneIsThai(r) {
+			return "", nil, false
+		}
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	if !hasLetter {
+		return "", nil, false
+	}
+
+	layouts := s.Layouts
+	if len(layouts) == 0 {
+		layouts = []*KeyboardLayout{Kedmanee, Pattachote}
+	}
+	gcp := s.Parser
+	if gcp == nil {
+		gcp = NewGStackClusterParser()
+	}
+
+	var best *KeyboardLayout
+	bestThai := ""
+	bestHits := -1
+	for _, layout := range layouts {
+		thai := layout.FromQWERTY(text)
+		hits, ok := s.scoreThai(gcp, thai)
+		if ok && hits > bestHits {
+			best, bestThai, bestHits = layout, thai, hits
+		}
+	}
+	if best == nil {
+		return "", nil, false
+	}
+	return bestThai, best, true
+}
+
+// If the text looks like Latin text, such as English, typed in a Thai
+// layout, return the Latin text and the layout it was typed in. Text
+// which has Latin letters in it already, or which is believable Thai,
+// as MistypedThai judges it, is never mistyped.
+func (s *LayoutDetector) MistypedLatin(text string) (string, *KeyboardLayout, bool) {
+	hasThai := false
+	for _, r := range text {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			return "", nil, false
+		}
+		if RuneIsThai(r) {
+			hasThai = true
+		}
+	}
+	if !hasThai {
+		return "", nil, false
+	}
+
+	layouts := s.Layouts
+	if len(layouts) == 0 {
+		layouts = []*KeyboardLayout{Kedmanee, Pattachote}
+	}
+	gcp := s.Parser
+	if gcp == nil {
+		gcp = NewGStackClusterParser()
+	}
+	if _, ok := s.scoreThai(gcp, text); ok {
+		return "", nil, false
+	}
+
+	var best *KeyboardLayout
+	bestLatin := ""
+	bestHits := -1
+	for _, layout := range layouts {
+		latin := layout.ToQWERTY(text)
+		hits, ok := s.scoreLatin(latin)
+		if ok && hits > bestHits {
+			best, bestLatin, bestHits = layout, latin, hits
+		}
+	}
+	if best == nil {
+		return "", nil, false
+	}
+	return bestLatin, best, true
+}
+
+// Is the converted text believable Latin text? If so, return how many
+// of its words are in LatinWords.
+func (s *LayoutDetector) scoreLatin(latin string) (int, bool) {
+	words := 0
+	hits := 0
+	for _, word := range strings.Fields(latin) {
+		word = strings.TrimFunc(word, unicode.IsPunct)
+		if word == "" {
+			continue
+		}
+		hasVowel := false
+		for _, r := range word {
+			if r >= unicode.MaxASCII || !unicode.IsLetter(r) {
+				return 0, false
+			}
+			if strings.ContainsRune("aeiouyAEIOUY", r) {
+				hasVowel = true
+			}
+		}
+		if s.LatinWords == nil && !hasVowel {
+			return 0, false
+		}
+		words++
+		if s.LatinWords != nil && s.LatinWords.Has(strings.ToLower(word)) {
+			hits++
+		}
+	}
+	if words == 0 {
+		return 0, false
+	}
+	if s.LatinWords == nil {
+		return 0, true
+	}
+	return hits, hits*2 > words
+}
+
+// Is the converted text believable Thai? If so, return how many of its
+// clusters are in dictionary words.
+func (s *LayoutDetector) scoreThai(gcp *GStackClusterParser, thai string) (int, bool) {
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks(thai))
+	thaiClusters := 0
+	for i := range clusters {
+		cc := &clusters[i]
+		if StringIsThai(cc.Text) {
+			if !cc.IsValidThai {
+				return 0, false
+			}
+			thaiClusters++
+			continue
+		}
+		// Spaces and punctuation can be in Thai text, but not
+		// anything else the keys could not have typed
+		for _, r := range cc.Text {
+			if !unicode.IsSpace(r) && !unicode.IsPunct(r) {
+				return 0, false
+			}
+		}
+	}
+	if thaiClusters == 0 {
+		return 0, false
+	}
+	if s.Dictionary == nil {
+		return 0, true
+	}
+
+	hits := 0
+	segmenter := &WordSegmenter{Dictionary: s.Dictionary}
+	for _, w := range segmenter.SegmentGStackClusters(clusters) {
+		if w.InDictionary {
+			hits += len(w.Clusters)
+		}
+	}
+	return hits, hits*2 > thaiClusters
+}
@@ -0,0 +1,90 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestKeyboardLayoutConvert(c *C) {
+	c.Check(Kedmanee.FromQWERTY("l;ylfu"), Equals, "สวัสดี")
+	c.Check(Kedmanee.ToQWERTY("สวัสดี"), Equals, "l;ylfu")
+	c.Check(Kedmanee.ToQWERTY("้ำสสน"), Equals, "hello")
+	// Shifted keys
+	c.Check(Kedmanee.FromQWERTY("8iy[ P"), Equals, "ครับ ญ")
+	c.Check(Kedmanee.FromQWERTY("@#$"), Equals, "๑๒๓")
+	// Characters which are not on the keyboard are kept
+	c.Check(Kedmanee.FromQWERTY("l;ylfu\n"), Equals, "สวัสดี\n")
+
+	c.Check(Pattachote.FromQWERTY("mgomhe"), Equals, "สัวสีย")
+	c.Check(Pattachote.ToQWERTY("สวัสดี"), Equals, "mogmuh")
+}
+
+func (s *MySuite) TestKeyboardLayoutsComplete(c *C) {
+	for _, layout := range []*KeyboardLayout{Kedmanee, Pattachote} {
+		c.Check(layout.Keys, HasLen, 47, Commentf("%s", layout.Name))
+		// Every printable ASCII character is on the US keyboard
+		for r := rune('!'); r <= '~'; r++ {
+			_, has := layout.fromQWERTY[r]
+			c.Check(has, Equals, true,
+				Commentf("%s: %c", layout.Name, r))
+		}
+	}
+}
+
+func (s *MySuite) TestLayoutDetector(c *C) {
+	var d LayoutDetector
+	thai, layout, ok := d.MistypedThai("l;ylfu")
+	c.Check(ok, Equals, true)
+	c.Check(thai, Equals, "สวัสดี")
+	c.Check(layout, Equals, Kedmanee)
+
+	// ้ cannot begin a cluster
+	_, _, ok = d.MistypedThai("hello")
+	c.Check(ok, Equals, false)
+	// Already Thai, or no letters
+	_, _, ok = d.MistypedThai("สวัสดี")
+	c.Check(ok, Equals, false)
+	_, _, ok = d.MistypedThai("123")
+	c.Check(ok, Equals, false)
+
+	// Without a dictionary, some English words are valid Thai
+	_, _, ok = d.MistypedThai("test")
+	c.Check(ok, Equals, true)
+
+	dict := NewDictionary()
+	dict.Add("สวัสดี", "ครับ")
+	d.Dictionary = dict
+	thai, layout, ok = d.MistypedThai("l;ylfu 8iy[")
+	c.Check(ok, Equals, true)
+	c.Check(thai, Equals, "สวัสดี ครับ")
+	c.Check(layout, Equals, Kedmanee)
+	_, _, ok = d.MistypedThai("test")
+	c.Check(ok, Equals, false)
+}
+
+func (s *MySuite) TestLayoutDetectorLatin(c *C) {
+	var d LayoutDetector
+	typed := Kedmanee.FromQWERTY("hello world")
+	latin, layout, ok := d.MistypedLatin(typed)
+	c.Check(ok, Equals, true)
+	c.Check(latin, Equals, "hello world")
+	c.Check(layout, Equals, Kedmanee)
+
+	// Believable Thai, Latin letters, and no Thai at all
+	_, _, ok = d.MistypedLatin("สวัสดีครับ")
+	c.Check(ok, Equals, false)
+	_, _, ok = d.MistypedLatin(typed + " ok")
+	c.Check(ok, Equals, false)
+	_, _, ok = d.MistypedLatin("123")
+	c.Check(ok, Equals, false)
+
+	words := NewDictionary()
+	words.Add("hello", "world")
+	d.LatinWords = words
+	typed = Pattachote.FromQWERTY("Hello world")
+	latin, layout, ok = d.MistypedLatin(typed)
+	c.Check(ok, Equals, true)
+	c.Check(latin, Equals, "Hello world")
+	c.Check(layout, Equals, Pattachote)
+	_, _, ok = d.MistypedLatin(Kedmanee.FromQWERTY("qwrt zxcv"))
+	c.Check(ok, Equals, false)
+}
@@ -0,0 +1,148 @@
+package paasaathai
+
+// At each position, ParseGraphemeStacks takes the first rule that
+// matches, and drops the others, though some text is ambiguous, like
+// the แม่ระมาด and เพคะ noted with the rules. A ClusterLattice keeps
+// every cluster that any rule could make, so that a word segmenter or
+// a scorer can choose among them.
+
+// A cluster that a rule could make, from one stack position to another
+type ClusterLatticeEdge struct {
+	// The positions of the GraphemeStacks in the lattice, with an
+	// exclusive end
+	Start int
+	End   int
+
+	// The cluster; its MatchingRule is the name of the rule that made
+	// it, or "" for a non-Thai stack or a stack that no rule matched
+	Cluster GStackCluster
+}
+
+// Every cluster that the rules could make, at every stack position.
+// It is a DAG whose nodes are the positions between the stacks, from 0
+// to len(Stacks), and whose edges are the clusters.
+type ClusterLattice struct {
+	Stacks []GraphemeStack
+
+	// Edges[i] are the clusters that start at stack i, in the order
+	// that their rules are tried. There is at least one for each
+	// stack, even for the stacks that no path reaches.
+	Edges [][]ClusterLatticeEdge
+}
+
+// Make the lattice of every cluster that the rules could make
+func (s *GStackClusterParser) ParseLattice(input []GraphemeStack) *ClusterLattice {
+	lattice := &ClusterLattice{
+		Stacks: input,
+		Edges:  make([][]ClusterLatticeEdge, len(input)),
+	}
+	for i := range input {
+		lattice.Edges[i] = s.latticeEdges(input, i)
+	}
+	return lattice
+}
+
+// The clusters that start at stack i, the way that ParseGraphemeStacks
+// would make them if it tried every rule
+func (s *GStackClusterParser) latticeEdges(input []GraphemeStack, i int) []ClusterLatticeEdge {
+	if !input[i].IsThai() {
+		c := makeCluster(input[i : i+1])
+		c.FirstConsonant = input[i]
+		return []ClusterLatticeEdge{{Start: i, End: i + 1, Cluster: c}}
+	}
+
+	var edges []ClusterLatticeEdge
+	for r := range s.rules {
+		rule := &s.rules[r]
+		var c GStackCluster
+		var length int
+		if rule.match(input, i, &length, &c) {
+			c.MatchingRule = rule.name
+			edges = append(edges, ClusterLatticeEdge{Start: i, End: i + length, Cluster: c})
+		}
+	}
+	if len(edges) > 0 {
+		return edges
+	}
+
+	c := makeCluster(input[i : i+1])
+	c.InvalidThai = input[i]
+	c.IsValidThai = false
+	if c.InvalidReason == NoInvalidReason {
+		c.InvalidReason = ReasonUnmatched
+	}
+	return []ClusterLatticeEdge{{Start: i, End: i + 1, Cluster: c}}
+}
+
+// The clusters that ParseGraphemeStacks chooses: the first edge at
+// each position, starting from 0
+func (s *ClusterLattice) GreedyPath() []GStackCluster {
+	clusters := make([]GStackCluster, 0, len(s.Stacks))
+	for i := 0; i < len(s.Stacks); {
+		e := &s.Edges[i][0]
+		clusters = append(clusters, e.Cluster)
+		i = e.End
+	}
+	return clusters
+}
+
+// The clusters of the path through the lattice whose edges have the
+// highest total score. Of paths with the same score, the one with the
+// fewest clusters is chosen, and then the one whose rules come first.
+func (s *ClusterLattice) BestPath(score func(e *ClusterLatticeEdge) float64) []GStackCluster {
+	type bestPath struct {
+		reached bool
+		score   float64
+		edges   int
+		prev    *ClusterLatticeEdge
+	}
+	paths := make([]bestPath, len(s.Stacks)+1)
+	paths[0].reached = true
+
+	for i := range s.Edges {
+		if !paths[i].reached {
+			continue
+		}
+		for k := range s.Edges[i] {
+			e := &s.Edges[i][k]
+			candidate := bestPath{
+				reached: true,
+				score:   paths[i].score + score(e),
+				edges:   paths[i].edges + 1,
+				prev:    e,
+			}
+			p := &paths[e.End]
+			if !p.reached || candidate.score > p.score ||
+				(candidate.score == p.score && candidate.edges < p.edges) {
+				*p = candidate
+			}
+		}
+	}
+
+	// Walk the path backwards
+	clusters := make([]GStackCluster, paths[len(s.Stacks)].edges)
+	for j, k := len(s.Stacks), len(clusters)-1; j > 0; k-- {
+		e := paths[j].prev
+		clusters[k] = e.Cluster
+		j = e.Start
+	}
+	return clusters
+}
+
+// The number of paths through the lattice, which grows quickly with
+// its length. It saturates at the largest int.
+func (s *ClusterLattice) PathCount() int {
+	const maxInt = int(^uint(0) >> 1)
+	counts := make([]int, len(s.Stacks)+1)
+	counts[0] = 1
+	for i := range s.Edges {
+		for _, e := range s.Edges[i] {
+			if counts[e.End] > maxInt-counts[i] {
+				counts[e.End] = maxInt
+			} else {
+				counts[e.End] += counts[i]
+			}
+		}
+	}
+	return counts[len(s.Stacks)]
+}
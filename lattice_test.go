@@ -0,0 +1,50 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func clusterTexts(clusters []GStackCluster) []string {
+	texts := make([]string, len(clusters))
+	for i := range clusters {
+		texts[i] = clusters[i].Text
+	}
+	return texts
+}
+
+func (s *MySuite) TestClusterLattice(c *C) {
+	gcp := NewGStackClusterParser()
+	lattice := gcp.ParseLattice(ParseGraphemeStacks("สวัสดี"))
+
+	c.Assert(lattice.Edges, HasLen, 4)
+	var rules []string
+	for _, e := range lattice.Edges[1] {
+		c.Check(e.Start, Equals, 1)
+		rules = append(rules, e.Cluster.MatchingRule)
+	}
+	c.Check(rules, DeepEquals, []string{"mai_han_akat", "solo_diacritic"})
+	c.Check(lattice.Edges[1][0].End, Equals, 3)
+	c.Check(lattice.PathCount(), Equals, 4)
+
+	c.Check(clusterTexts(lattice.GreedyPath()), DeepEquals,
+		[]string{"ส", "วัส", "ดี"})
+
+	// Prefer single consonants
+	path := lattice.BestPath(func(e *ClusterLatticeEdge) float64 {
+		if e.Cluster.MatchingRule == "consonant" {
+			return 1
+		}
+		return 0
+	})
+	c.Check(clusterTexts(path), DeepEquals, []string{"ส", "วั", "ส", "ดี"})
+	c.Check(path[3].MatchingRule, Equals, "single_diacritic_vowel")
+}
+
+func (s *MySuite) TestClusterLatticeGreedyPath(c *C) {
+	gcp := NewGStackClusterParser()
+	for _, text := range []string{"แม่ระมาด", "เพคะ", "hello ไทย", "ุกา", ""} {
+		stacks := ParseGraphemeStacks(text)
+		c.Check(gcp.ParseLattice(stacks).GreedyPath(), DeepEquals,
+			gcp.ParseGraphemeStacks(stacks), Commentf("%s", text))
+	}
+}
@@ -0,0 +1,169 @@
+package paasaathai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The kinds of typing errors that Normalize can repair. They can be
+// combined, to choose which ones NormalizeWith repairs.
+type NormalizeFix uint
+
+const (
+	// A tone mark typed before the upper or lower vowel of the
+	// same stack, as in ก่ี for กี่
+	FixToneMarkOrder NormalizeFix = 1 << iota
+
+	// The same vowel, tone mark, or sign typed more than once in a
+	// row, as in ก่่ for ก่
+	FixDuplicateDiacritic
+
+	// A nikhahit and sara aa typed instead of a sara am, as in นํ้า
+	// for น้ำ
+	FixSaraAm
+
+	// The wrong front vowel: two sara e for a sara ae, as in เเละ for
+	// และ, and a sara ae for the sara e of the short o ang vowel, as in
+	// แกาะ for เกาะ
+	FixFrontVowel
+
+	AllNormalizeFixes = FixToneMarkOrder | FixDuplicateDiacritic |
+		FixSaraAm | FixFrontVowel
+)
+
+func (s NormalizeFix) String() string {
+	switch s {
+	case FixToneMarkOrder:
+		return "ToneMarkOrder"
+	case FixDuplicateDiacritic:
+		return "DuplicateDiacritic"
+	case FixSaraAm:
+		return "SaraAm"
+	case FixFrontVowel:
+		return "FrontVowel"
+	default:
+		return fmt.Sprintf("NormalizeFix(%d)", uint(s))
+	}
+}
+
+// One repair made by Normalize
+type NormalizeEdit struct {
+	Fix NormalizeFix
+
+	// The span of the original text that was replaced, in bytes, with
+	// an exclusive end
+	StartByte int
+	EndByte   int
+
+	Original    string
+	Replacement string
+}
+
+// Implements the fmt.Stringer interface
+func (s NormalizeEdit) String() string {
+	return fmt.Sprintf("%d-%d: %s: %q -> %q", s.StartByte, s.EndByte,
+		s.Fix, s.Original, s.Replacement)
+}
+
+// Repair common Thai typing errors, returning the corrected text and
+// the edits that were made, in order. Text with nothing to repair is
+// returned as-is.
+func Normalize(text string) (string, []NormalizeEdit) {
+	return NormalizeWith(text, AllNormalizeFixes)
+}
+
+// Like Normalize, but only make the repairs in fixes
+func NormalizeWith(text string, fixes NormalizeFix) (string, []NormalizeEdit) {
+	// The code points, and where each one starts
+	runes := make([]rune, 0, len(text))
+	starts := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		runes = append(runes, r)
+		starts = append(starts, i)
+	}
+	starts = append(starts, len(text))
+
+	at := func(i int) rune {
+		if i < len(runes) {
+			return runes[i]
+		}
+		return 0
+	}
+
+	var sb strings.Builder
+	var edits []NormalizeEdit
+	// Replace the runes from i up to j
+	replace := func(fix NormalizeFix, i int, j int, replacement ...rune) {
+		edit := NormalizeEdit{
+			Fix:         fix,
+			StartByte:   starts[i],
+			EndByte:     starts[j],
+			Original:    text[starts[i]:starts[j]],
+			Replacement: string(replacement),
+		}
+		edits = append(edits, edit)
+		sb.WriteString(edit.Replacement)
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case fixes&FixFrontVowel != 0 && r == THAI_CHARACTER_SARA_E &&
+			at(i+1) == THAI_CHARACTER_SARA_E:
+			replace(FixFrontVowel, i, i+2, THAI_CHARACTER_SARA_AE)
+			i += 2
+
+		case fixes&FixFrontVowel != 0 && r == THAI_CHARACTER_SARA_AE &&
+			isShortOAngAfterFrontVowel(runes[i+1:]):
+			replace(FixFrontVowel, i, i+1, THAI_CHARACTER_SARA_E)
+			i++
+
+		case fixes&FixSaraAm != 0 && r == THAI_CHARACTER_NIKHAHIT &&
+			at(i+1) == THAI_CHARACTER_SARA_AA:
+			replace(FixSaraAm, i, i+2, THAI_CHARACTER_SARA_AM)
+			i += 2
+
+		// The tone mark goes between the consonant and the sara am
+		case fixes&FixSaraAm != 0 && r == THAI_CHARACTER_NIKHAHIT &&
+			RuneIsToneMark(at(i+1)) && at(i+2) == THAI_CHARACTER_SARA_AA:
+			replace(FixSaraAm, i, i+3, runes[i+1], THAI_CHARACTER_SARA_AM)
+			i += 3
+
+		case fixes&FixDuplicateDiacritic != 0 && RuneIsDiacritic(r) &&
+			at(i+1) == r:
+			j := i + 1
+			for at(j) == r {
+				j++
+			}
+			replace(FixDuplicateDiacritic, i, j, r)
+			i = j
+
+		case fixes&FixToneMarkOrder != 0 && RuneIsToneMark(r) &&
+			(RuneIsUpperPositionVowel(at(i+1)) || RuneIsLowerPositionVowel(at(i+1))):
+			replace(FixToneMarkOrder, i, i+2, runes[i+1], r)
+			i += 2
+
+		default:
+			// Copy the bytes, so that invalid UTF-8 is kept as it is
+			sb.WriteString(text[starts[i]:starts[i+1]])
+			i++
+		}
+	}
+
+	if len(edits) == 0 {
+		return text, nil
+	}
+	return sb.String(), edits
+}
+
+// Do the runes, which follow a front vowel, finish the short o ang
+// vowel, as in เกาะ or เพราะ? That is one or two consonants, a sara aa,
+// and a sara a.
+func isShortOAngAfterFrontVowel(runes []rune) bool {
+	n := 0
+	for n < len(runes) && n < 2 && RuneIsConsonant(runes[n]) {
+		n++
+	}
+	return n > 0 && len(runes) >= n+2 &&
+		runes[n] == THAI_CHARACTER_SARA_AA && runes[n+1] == THAI_CHARACTER_SARA_A
+}
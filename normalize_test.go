@@ -0,0 +1,83 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestNormalizeNothing(c *C) {
+	text, edits := Normalize("น้ำและเกาะ abc")
+	c.Check(text, Equals, "น้ำและเกาะ abc")
+	c.Check(edits, IsNil)
+}
+
+func (s *MySuite) TestNormalizeToneMarkOrder(c *C) {
+	// THAI_CHARACTER_KO_KAI, THAI_CHARACTER_MAI_EK, THAI_CHARACTER_SARA_II
+	text, edits := Normalize("xก่ี")
+	c.Check(text, Equals, "xกี่")
+	c.Assert(edits, HasLen, 1)
+	c.Check(edits[0], DeepEquals, NormalizeEdit{
+		Fix:         FixToneMarkOrder,
+		StartByte:   4,
+		EndByte:     10,
+		Original:    "่ี",
+		Replacement: "ี่",
+	})
+}
+
+// Invalid UTF-8 is not changed, even when a fix is made next to it
+func (s *MySuite) TestNormalizeInvalidUTF8(c *C) {
+	text, edits := Normalize("\xffเเก\xfe")
+	c.Check(text, Equals, "\xffแก\xfe")
+	c.Assert(edits, HasLen, 1)
+	c.Check(edits[0].Fix, Equals, FixFrontVowel)
+	c.Check(edits[0].StartByte, Equals, 1)
+	c.Check(edits[0].EndByte, Equals, 7)
+}
+
+func (s *MySuite) TestNormalizeDuplicateDiacritic(c *C) {
+	text, edits := Normalize("ก่่่า")
+	c.Check(text, Equals, "ก่า")
+	c.Assert(edits, HasLen, 1)
+	c.Check(edits[0].Fix, Equals, FixDuplicateDiacritic)
+	c.Check(edits[0].StartByte, Equals, 3)
+	c.Check(edits[0].EndByte, Equals, 12)
+}
+
+func (s *MySuite) TestNormalizeSaraAm(c *C) {
+	// THAI_CHARACTER_NIKHAHIT, THAI_CHARACTER_SARA_AA
+	text, edits := Normalize("กํา")
+	c.Check(text, Equals, "กำ")
+	c.Assert(edits, HasLen, 1)
+	c.Check(edits[0].Fix, Equals, FixSaraAm)
+
+	// THAI_CHARACTER_NIKHAHIT, THAI_CHARACTER_MAI_THO, THAI_CHARACTER_SARA_AA
+	text, edits = Normalize("นํ้า")
+	c.Check(text, Equals, "น้ำ")
+	c.Assert(edits, HasLen, 1)
+	c.Check(edits[0].Replacement, Equals, "้ำ")
+}
+
+func (s *MySuite) TestNormalizeFrontVowel(c *C) {
+	text, edits := Normalize("เเละแพราะ")
+	c.Check(text, Equals, "และเพราะ")
+	c.Assert(edits, HasLen, 2)
+	c.Check(edits[0].String(), Equals, `0-6: FrontVowel: "เเ" -> "แ"`)
+	c.Check(edits[1].StartByte, Equals, 12)
+	c.Check(edits[1].Replacement, Equals, "เ")
+
+	// Not every sara ae is wrong
+	text, edits = Normalize("แกะ")
+	c.Check(text, Equals, "แกะ")
+	c.Check(edits, IsNil)
+}
+
+func (s *MySuite) TestNormalizeWith(c *C) {
+	input := "เเก่่"
+	text, edits := NormalizeWith(input, FixDuplicateDiacritic)
+	c.Check(text, Equals, "เเก่")
+	c.Assert(edits, HasLen, 1)
+
+	text, edits = NormalizeWith(input, 0)
+	c.Check(text, Equals, input)
+	c.Check(edits, IsNil)
+}
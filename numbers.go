@@ -0,0 +1,302 @@
+package paasaathai
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Replace the Arabic digits 0-9 with Thai digits
+func ArabicToThaiDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return THAI_DIGIT_ZERO + r - '0'
+		}
+		return r
+	}, text)
+}
+
+// Replace the Thai digits with Arabic digits 0-9
+func ThaiToArabicDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		if RuneIsDigit(r) {
+			return '0' + r - THAI_DIGIT_ZERO
+		}
+		return r
+	}, text)
+}
+
+const (
+	thaiWordNegative = "ลบ"
+	thaiWordPoint    = "จุด"
+	thaiWordMillion  = "ล้าน"
+	thaiWordEt       = "เอ็ด"
+	thaiWordYi       = "ยี่"
+	thaiWordBaht     = "บาท"
+	thaiWordSatang   = "สตางค์"
+	thaiWordEven     = "ถ้วน"
+)
+
+var thaiDigitWords = []string{"ศูนย์", "หนึ่ง", "สอง", "สาม", "สี่", "ห้า", "หก",
+	"เจ็ด", "แปด", "เก้า"}
+
+// The words for the places of a number below one million, from the
+// ones place, which has none, to the hundred thousands
+var thaiPlaceWords = []string{"", "สิบ", "ร้อย", "พัน", "หมื่น", "แสน"}
+
+// Spell out a number in Thai words, as in หนึ่งร้อยยี่สิบเอ็ด for 121
+func NumberToThaiWords(n int64) string {
+	if n < 0 {
+		// -n overflows for math.MinInt64, but its uint64 does not
+		return thaiWordNegative + uintToThaiWords(uint64(-(n+1))+1)
+	}
+	return uintToThaiWords(uint64(n))
+}
+
+func uintToThaiWords(n uint64) string {
+	if n == 0 {
+		return thaiDigitWords[0]
+	}
+	var sb strings.Builder
+	writeThaiWords(&sb, n, false)
+	return sb.String()
+}
+
+// Write the words for a number which is not 0. If there are higher
+// digits before it, a 1 in the ones place is read as เอ็ด.
+func writeThaiWords(sb *strings.Builder, n uint64, hasHigherDigits bool) {
+	if n >= 1000000 {
+		writeThaiWords(sb, n/1000000, hasHigherDigits)
+		sb.WriteString(thaiWordMillion)
+		n %= 1000000
+		hasHigherDigits = true
+		if n == 0 {
+			return
+		}
+	}
+
+	var digits [6]int
+	for place := range digits {
+		digits[place] = int(n % 10)
+		n /= 10
+	}
+	for place := len(digits) - 1; place >= 0; place-- {
+		d := digits[place]
+		switch {
+		case d == 0:
+			continue
+		case place == 1 && d == 1:
+			// สิบ, not หนึ่งสิบ
+		case place == 1 && d == 2:
+			sb.WriteString(thaiWordYi)
+		case place == 0 && d == 1 && hasHigherDigits:
+			sb.WriteString(thaiWordEt)
+		default:
+			sb.WriteString(thaiDigitWords[d])
+		}
+		sb.WriteString(thaiPlaceWords[place])
+		hasHigherDigits = true
+	}
+}
+
+// Spell out a decimal number, like "-12.05", in Thai words. The digits
+// after the decimal point are read one by one, as in
+// สิบสองจุดศูนย์ห้า. Thai digits may be used.
+func DecimalToThaiWords(decimal string) (string, error) {
+	text := ThaiToArabicDigits(decimal)
+	negative := strings.HasPrefix(text, "-")
+	if negative {
+		text = text[1:]
+	}
+	whole, fraction, hasPoint := strings.Cut(text, ".")
+	if whole == "" || (hasPoint && fraction == "") ||
+		!isArabicDigits(whole) || !isArabicDigits(fraction) {
+		return "", fmt.Errorf("%q is not a decimal number", decimal)
+	}
+
+	var n uint64
+	for _, r := range whole {
+		if n > (math.MaxUint64-9)/10 {
+			return "", fmt.Errorf("%q is too large", decimal)
+		}
+		n = n*10 + uint64(r-'0')
+	}
+
+	var sb strings.Builder
+	if negative {
+		sb.WriteString(thaiWordNegative)
+	}
+	sb.WriteString(uintToThaiWords(n))
+	if hasPoint {
+		sb.WriteString(thaiWordPoint)
+		for _, r := range fraction {
+			sb.WriteString(thaiDigitWords[r-'0'])
+		}
+	}
+	return sb.String(), nil
+}
+
+func isArabicDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// The words that ThaiWordsToNumber understands, longest first so that
+// the longest match is tried first
+var thaiNumberWords = func() []thaiNumberWord {
+	words := []thaiNumberWord{
+		{thaiWordEt, 1, 0},
+		{thaiWordYi, 2, 0},
+		{thaiWordMillion, 0, 1000000},
+	}
+	for d, word := range thaiDigitWords {
+		words = append(words, thaiNumberWord{word, d, 0})
+	}
+	multiplier := 1
+	for _, word := range thaiPlaceWords {
+		if word != "" {
+			words = append(words, thaiNumberWord{word, 0, multiplier})
+		}
+		multiplier *= 10
+	}
+	for i := 1; i < len(words); i++ {
+		for j := i; j > 0 && len(words[j].text) > len(words[j-1].text); j-- {
+			words[j], words[j-1] = words[j-1], words[j]
+		}
+	}
+	return words
+}()
+
+type thaiNumberWord struct {
+	text string
+
+	// For digits, the digit; for places, the multiplier
+	digit      int
+	multiplier int
+}
+
+// Read a number spelled out in Thai words, like หนึ่งร้อยยี่สิบเอ็ด.
+// Spaces between the words are allowed.
+func ThaiWordsToNumber(words string) (int64, error) {
+	text := strings.Join(strings.Fields(words), "")
+	negative := strings.HasPrefix(text, thaiWordNegative)
+	if negative {
+		text = text[len(thaiWordNegative):]
+	}
+	n, err := parseThaiWords(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", words, err)
+	}
+	switch {
+	case negative && n <= -math.MinInt64:
+		return -int64(n-1) - 1, nil
+	case !negative && n <= math.MaxInt64:
+		return int64(n), nil
+	default:
+		return 0, fmt.Errorf("%q is too large", words)
+	}
+}
+
+func parseThaiWords(text string) (uint64, error) {
+	if text == "" {
+		return 0, fmt.Errorf("there is no number")
+	}
+	if text == thaiDigitWords[0] {
+		return 0, nil
+	}
+
+	var total, group uint64
+	digit := -1
+	// The multiplier of the last place in the group, which must be
+	// larger than the next one
+	lastMultiplier := 10000000
+	sawMillion := false
+	for text != "" {
+		var word *thaiNumberWord
+		for i := range thaiNumberWords {
+			if strings.HasPrefix(text, thaiNumberWords[i].text) {
+				word = &thaiNumberWords[i]
+				break
+			}
+		}
+		if word == nil {
+			return 0, fmt.Errorf("unknown word at %q", text)
+		}
+		text = text[len(word.text):]
+
+		switch {
+		case word.multiplier == 0:
+			if digit != -1 {
+				return 0, fmt.Errorf("two digits in a row before %q", text)
+			}
+			if word.digit == 0 {
+				return 0, fmt.Errorf("%s can only be used by itself", thaiDigitWords[0])
+			}
+			digit = word.digit
+
+		case word.multiplier == 1000000:
+			if digit != -1 {
+				group += uint64(digit)
+			}
+			if group == 0 && !sawMillion {
+				group = 1
+			}
+			if total > (math.MaxUint64-group)/1000000 {
+				return 0, fmt.Errorf("the number is too large")
+			}
+			total = (total + group) * 1000000
+			group = 0
+			digit = -1
+			lastMultiplier = 10000000
+			sawMillion = true
+
+		default:
+			if word.multiplier >= lastMultiplier {
+				return 0, fmt.Errorf("%s is out of order", word.text)
+			}
+			if digit == -1 {
+				digit = 1
+			}
+			group += uint64(digit * word.multiplier)
+			digit = -1
+			lastMultiplier = word.multiplier
+		}
+	}
+	if digit != -1 {
+		group += uint64(digit)
+	}
+	if total > math.MaxUint64-group {
+		return 0, fmt.Errorf("the number is too large")
+	}
+	return total + group, nil
+}
+
+// Spell out an amount of money in Thai words, as written on cheques
+// and invoices. The amount is in satang, so 12150 is
+// หนึ่งร้อยยี่สิบเอ็ดบาทห้าสิบสตางค์. Whole amounts end in ถ้วน,
+// "exactly".
+func BahtText(satang int64) string {
+	var sb strings.Builder
+	amount := uint64(satang)
+	if satang < 0 {
+		sb.WriteString(thaiWordNegative)
+		amount = uint64(-(satang + 1)) + 1
+	}
+	baht, satangs := amount/100, amount%100
+
+	if baht > 0 || satangs == 0 {
+		sb.WriteString(uintToThaiWords(baht))
+		sb.WriteString(thaiWordBaht)
+	}
+	if satangs == 0 {
+		sb.WriteString(thaiWordEven)
+	} else {
+		sb.WriteString(uintToThaiWords(satangs))
+		sb.WriteString(thaiWordSatang)
+	}
+	return sb.String()
+}
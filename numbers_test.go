@@ -0,0 +1,98 @@
+package paasaathai
+
+import (
+	"math"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestDigits(c *C) {
+	c.Check(ArabicToThaiDigits("พ.ศ. 2569, 10:45"), Equals, "พ.ศ. ๒๕๖๙, ๑๐:๔๕")
+	c.Check(ThaiToArabicDigits("พ.ศ. ๒๕๖๙, ๑๐:๔๕"), Equals, "พ.ศ. 2569, 10:45")
+}
+
+var thaiNumberTests = []struct {
+	n     int64
+	words string
+}{
+	{0, "ศูนย์"},
+	{1, "หนึ่ง"},
+	{10, "สิบ"},
+	{11, "สิบเอ็ด"},
+	{20, "ยี่สิบ"},
+	{21, "ยี่สิบเอ็ด"},
+	{101, "หนึ่งร้อยเอ็ด"},
+	{121, "หนึ่งร้อยยี่สิบเอ็ด"},
+	{1000, "หนึ่งพัน"},
+	{2026, "สองพันยี่สิบหก"},
+	{12345, "หนึ่งหมื่นสองพันสามร้อยสี่สิบห้า"},
+	{1000000, "หนึ่งล้าน"},
+	{1000001, "หนึ่งล้านเอ็ด"},
+	{21000000, "ยี่สิบเอ็ดล้าน"},
+	{1000000000000, "หนึ่งล้านล้าน"},
+	{2300000000000, "สองล้านสามแสนล้าน"},
+	{-15, "ลบสิบห้า"},
+}
+
+func (s *MySuite) TestNumberToThaiWords(c *C) {
+	for _, test := range thaiNumberTests {
+		c.Check(NumberToThaiWords(test.n), Equals, test.words)
+	}
+}
+
+func (s *MySuite) TestThaiWordsToNumber(c *C) {
+	for _, test := range thaiNumberTests {
+		n, err := ThaiWordsToNumber(test.words)
+		c.Check(err, IsNil)
+		c.Check(n, Equals, test.n, Commentf("%s", test.words))
+	}
+
+	n, err := ThaiWordsToNumber("หนึ่ง ร้อย ยี่สิบ")
+	c.Check(err, IsNil)
+	c.Check(n, Equals, int64(120))
+
+	// Without the หนึ่ง
+	n, err = ThaiWordsToNumber("ร้อยเอ็ด")
+	c.Check(err, IsNil)
+	c.Check(n, Equals, int64(101))
+
+	for _, words := range []string{"", "สองสาม", "สิบร้อย", "หนึ่งศูนย์", "สิบa"} {
+		_, err = ThaiWordsToNumber(words)
+		c.Check(err, NotNil, Commentf("%s", words))
+	}
+}
+
+func (s *MySuite) TestThaiWordsLimits(c *C) {
+	for _, n := range []int64{math.MaxInt64, math.MinInt64} {
+		words := NumberToThaiWords(n)
+		back, err := ThaiWordsToNumber(words)
+		c.Check(err, IsNil)
+		c.Check(back, Equals, n)
+	}
+	_, err := ThaiWordsToNumber("หนึ่งล้านล้านล้านล้าน")
+	c.Check(err, NotNil)
+}
+
+func (s *MySuite) TestDecimalToThaiWords(c *C) {
+	words, err := DecimalToThaiWords("-12.05")
+	c.Check(err, IsNil)
+	c.Check(words, Equals, "ลบสิบสองจุดศูนย์ห้า")
+
+	words, err = DecimalToThaiWords("๓.๑๔")
+	c.Check(err, IsNil)
+	c.Check(words, Equals, "สามจุดหนึ่งสี่")
+
+	for _, decimal := range []string{"", ".5", "1.", "1.2.3", "1e5"} {
+		_, err = DecimalToThaiWords(decimal)
+		c.Check(err, NotNil, Commentf("%s", decimal))
+	}
+}
+
+func (s *MySuite) TestBahtText(c *C) {
+	c.Check(BahtText(10000), Equals, "หนึ่งร้อยบาทถ้วน")
+	c.Check(BahtText(12150), Equals, "หนึ่งร้อยยี่สิบเอ็ดบาทห้าสิบสตางค์")
+	c.Check(BahtText(101), Equals, "หนึ่งบาทหนึ่งสตางค์")
+	c.Check(BahtText(21), Equals, "ยี่สิบเอ็ดสตางค์")
+	c.Check(BahtText(0), Equals, "ศูนย์บาทถ้วน")
+	c.Check(BahtText(-100000001), Equals, "ลบหนึ่งล้านบาทหนึ่งสตางค์")
+}
@@ -0,0 +1,351 @@
+package paasaathai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A way of writing Thai syllables in the Latin alphabet
+type RomanizationScheme interface {
+	RomanizeSyllable(s *Syllable) string
+
+	// What to write between two syllables that follow each other
+	SyllableSeparator() string
+
+	// What to write between a syllable and its repetition by the mai
+	// yamok (ๆ)
+	MaiYamokSeparator() string
+}
+
+// A RomanizationScheme made from lookup tables for the initial
+// consonant sounds, the final consonant sounds, and the vowels.
+type TableRomanizationScheme struct {
+	Name string
+
+	// The sound of each consonant at the start of a syllable
+	Initials map[rune]string
+
+	// The sound of each consonant at the end of a syllable
+	Finals map[rune]string
+
+	Vowels map[Vowel]string
+
+	// Combining diacritics to mark each tone. They are placed after
+	// the first vowel letter of the romanized vowel. If nil, tones
+	// are not marked.
+	Tones map[Tone]string
+
+	// Written after a short vowel in a syllable with no final
+	// consonant, such as the glottal stop in IPA
+	OpenShortFinal string
+
+	Separator string
+
+	// Written before the syllable that the mai yamok repeats
+	RepeatSeparator string
+}
+
+// The letters, in the romanization tables, that can carry a tone mark
+const romanVowelLetters = "aeiouəɛɔʉɯɤ"
+
+func (s *TableRomanizationScheme) MaiYamokSeparator() string {
+	return s.RepeatSeparator
+}
+
+func (s *TableRomanizationScheme) RomanizeSyllable(syllable *Syllable) string {
+	if syllable.Vowel == UndefinedVowel {
+		return syllable.Text
+	}
+	var sb strings.Builder
+	for _, r := range PronouncedInitials(syllable) {
+		sb.WriteString(s.Initials[r])
+	}
+
+	vowel := s.Vowels[syllable.Vowel]
+	toneMark := s.Tones[syllable.Tone]
+	if i := strings.IndexAny(vowel, romanVowelLetters); toneMark != "" && i >= 0 {
+		_, size := utf8.DecodeRuneInString(vowel[i:])
+		sb.WriteString(vowel[:i+size])
+		sb.WriteString(toneMark)
+		sb.WriteString(vowel[i+size:])
+	} else {
+		sb.WriteString(vowel)
+	}
+
+	if syllable.FinalConsonant.Main != 0 {
+		sb.WriteString(s.Finals[syllable.FinalConsonant.Main])
+	} else if syllable.Vowel.IsShort() && !syllable.Vowel.EndsLive() {
+		sb.WriteString(s.OpenShortFinal)
+	}
+
+	if toneMark == "" {
+		return sb.String()
+	}
+	// Use the precomposed letters, like à, where they exist
+	return norm.NFC.String(sb.String())
+}
+
+func (s *TableRomanizationScheme) SyllableSeparator() string {
+	return s.Separator
+}
+
+// Return the consonants whose sounds begin the syllable. A leading
+// ho hip or o ang is not pronounced. Neither is the ro rua in จร, ซร,
+// ศร, and สร, and ทร is pronounced like ซ.
+func PronouncedInitials(syllable *Syllable) []rune {
+	initials := syllable.InitialConsonants
+	switch len(initials) {
+	case 0:
+		return nil
+	case 1:
+		return []rune{initials[0].Main}
+	}
+	first, second := initials[0].Main, initials[1].Main
+	switch {
+	case first == THAI_CHARACTER_HO_HIP || first == THAI_CHARACTER_O_ANG:
+		return []rune{second}
+	case first == THAI_CHARACTER_THO_THAHAN && second == THAI_CHARACTER_RO_RUA:
+		return []rune{THAI_CHARACTER_SO_SO}
+	case second == THAI_CHARACTER_RO_RUA &&
+		(first == THAI_CHARACTER_CHO_CHAN || first == THAI_CHARACTER_SO_SO ||
+			first == THAI_CHARACTER_SO_SALA || first == THAI_CHARACTER_SO_SUA):
+		return []rune{first}
+	}
+	return []rune{first, second}
+}
+
+// The Royal Thai General System of Transcription, from the
+// Royal Institute. It does not mark tones or vowel length.
+var RTGS = &TableRomanizationScheme{
+	Name: "RTGS",
+	Initials: map[rune]string{
+		THAI_CHARACTER_KO_KAI:         "k",
+		THAI_CHARACTER_KHO_KHAI:       "kh",
+		THAI_CHARACTER_KHO_KHUAT:      "kh",
+		THAI_CHARACTER_KHO_KHWAI:      "kh",
+		THAI_CHARACTER_KHO_KHON:       "kh",
+		THAI_CHARACTER_KHO_RAKHANG:    "kh",
+		THAI_CHARACTER_NGO_NGU:        "ng",
+		THAI_CHARACTER_CHO_CHAN:       "ch",
+		THAI_CHARACTER_CHO_CHING:      "ch",
+		THAI_CHARACTER_CHO_CHANG:      "ch",
+		THAI_CHARACTER_SO_SO:          "s",
+		THAI_CHARACTER_CHO_CHOE:       "ch",
+		THAI_CHARACTER_YO_YING:        "y",
+		THAI_CHARACTER_DO_CHADA:       "d",
+		THAI_CHARACTER_TO_PATAK:       "t",
+		THAI_CHARACTER_THO_THAN:       "th",
+		THAI_CHARACTER_THO_NANGMONTHO: "th",
+		THAI_CHARACTER_THO_PHUTHAO:    "th",
+		THAI_CHARACTER_NO_NEN:         "n",
+		THAI_CHARACTER_DO_DEK:         "d",
+		THAI_CHARACTER_TO_TAO:         "t",
+		THAI_CHARACTER_THO_THUNG:      "th",
+		THAI_CHARACTER_THO_THAHAN:     "th",
+		THAI_CHARACTER_THO_THONG:      "th",
+		THAI_CHARACTER_NO_NU:          "n",
+		THAI_CHARACTER_BO_BAIMAI:      "b",
+		THAI_CHARACTER_PO_PLA:         "p",
+		THAI_CHARACTER_PHO_PHUNG:      "ph",
+		THAI_CHARACTER_FO_FA:          "f",
+		THAI_CHARACTER_PHO_PHAN:       "ph",
+		THAI_CHARACTER_FO_FAN:         "f",
+		THAI_CHARACTER_PHO_SAMPHAO:    "ph",
+		THAI_CHARACTER_MO_MA:          "m",
+		THAI_CHARACTER_YO_YAK:         "y",
+		THAI_CHARACTER_RO_RUA:         "r",
+		THAI_CHARACTER_LO_LING:        "l",
+		THAI_CHARACTER_WO_WAEN:        "w",
+		THAI_CHARACTER_SO_SALA:        "s",
+		THAI_CHARACTER_SO_RUSI:        "s",
+		THAI_CHARACTER_SO_SUA:         "s",
+		THAI_CHARACTER_HO_HIP:         "h",
+		THAI_CHARACTER_LO_CHULA:       "l",
+		THAI_CHARACTER_O_ANG:          "",
+		THAI_CHARACTER_HO_NOKHUK:      "h",
+		// The vowel includes the consonant sound
+		THAI_CHARACTER_RU: "",
+		THAI_CHARACTER_LU: "",
+	},
+	Finals: map[rune]string{
+		THAI_CHARACTER_KO_KAI:         "k",
+		THAI_CHARACTER_KHO_KHAI:       "k",
+		THAI_CHARACTER_KHO_KHUAT:      "k",
+		THAI_CHARACTER_KHO_KHWAI:      "k",
+		THAI_CHARACTER_KHO_KHON:       "k",
+		THAI_CHARACTER_KHO_RAKHANG:    "k",
+		THAI_CHARACTER_NGO_NGU:        "ng",
+		THAI_CHARACTER_CHO_CHAN:       "t",
+		THAI_CHARACTER_CHO_CHANG:      "t",
+		THAI_CHARACTER_SO_SO:          "t",
+		THAI_CHARACTER_CHO_CHOE:       "t",
+		THAI_CHARACTER_YO_YING:        "n",
+		THAI_CHARACTER_DO_CHADA:       "t",
+		THAI_CHARACTER_TO_PATAK:       "t",
+		THAI_CHARACTER_THO_THAN:       "t",
+		THAI_CHARACTER_THO_NANGMONTHO: "t",
+		THAI_CHARACTER_THO_PHUTHAO:    "t",
+		THAI_CHARACTER_NO_NEN:         "n",
+		THAI_CHARACTER_DO_DEK:         "t",
+		THAI_CHARACTER_TO_TAO:         "t",
+		THAI_CHARACTER_THO_THUNG:      "t",
+		THAI_CHARACTER_THO_THAHAN:     "t",
+		THAI_CHARACTER_THO_THONG:      "t",
+		THAI_CHARACTER_NO_NU:          "n",
+		THAI_CHARACTER_BO_BAIMAI:      "p",
+		THAI_CHARACTER_PO_PLA:         "p",
+		THAI_CHARACTER_PHO_PHAN:       "p",
+		THAI_CHARACTER_FO_FAN:         "p",
+		THAI_CHARACTER_PHO_SAMPHAO:    "p",
+		THAI_CHARACTER_MO_MA:          "m",
+		THAI_CHARACTER_YO_YAK:         "i",
+		THAI_CHARACTER_RO_RUA:         "n",
+		THAI_CHARACTER_LO_LING:        "n",
+		THAI_CHARACTER_WO_WAEN:        "o",
+		THAI_CHARACTER_SO_SALA:        "t",
+		THAI_CHARACTER_SO_RUSI:        "t",
+		THAI_CHARACTER_SO_SUA:         "t",
+		THAI_CHARACTER_LO_CHULA:       "n",
+	},
+	Vowels: map[Vowel]string{
+		VowelA:    "a",
+		VowelAA:   "a",
+		VowelI:    "i",
+		VowelII:   "i",
+		VowelUE:   "ue",
+		VowelUEE:  "ue",
+		VowelU:    "u",
+		VowelUU:   "u",
+		VowelE:    "e",
+		VowelEE:   "e",
+		VowelAE:   "ae",
+		VowelAEE:  "ae",
+		VowelO:    "o",
+		VowelOO:   "o",
+		VowelAW:   "o",
+		VowelAAW:  "o",
+		VowelOE:   "oe",
+		VowelOEE:  "oe",
+		VowelIA:   "ia",
+		VowelIAA:  "ia",
+		VowelUEA:  "uea",
+		VowelUEAA: "uea",
+		VowelUA:   "ua",
+		VowelUAA:  "ua",
+		VowelAM:   "am",
+		VowelAI:   "ai",
+		VowelAO:   "ao",
+		VowelRUE:  "rue",
+		VowelRUEE: "rue",
+		VowelLUE:  "lue",
+		VowelLUEE: "lue",
+	},
+	RepeatSeparator: " ",
+}
+
+// Romanize syllables that have already been parsed. Thai digits become
+// Arabic digits, the mai yamok (ๆ) repeats the previous syllable, and
+// anything else that is not a syllable is copied as-is. The scheme's
+// separators are written between syllables that follow each other,
+// and before a repeated syllable.
+func RomanizeSyllables(syllables []Syllable, scheme RomanizationScheme) string {
+	var sb strings.Builder
+	previous := ""
+	afterSyllable := false
+	for i := range syllables {
+		syllable := &syllables[i]
+		if syllable.Vowel != UndefinedVowel {
+			if afterSyllable {
+				sb.WriteString(scheme.SyllableSeparator())
+			}
+			previous = scheme.RomanizeSyllable(syllable)
+			sb.WriteString(previous)
+			afterSyllable = true
+			continue
+		}
+		afterSyllable = false
+		for _, r := range syllable.Text {
+			switch {
+			case RuneIsDigit(r):
+				sb.WriteRune('0' + r - THAI_DIGIT_ZERO)
+			case r == THAI_CHARACTER_MAIYAMOK:
+				if previous != "" {
+					sb.WriteString(scheme.MaiYamokSeparator())
+					sb.WriteString(previous)
+				}
+			default:
+				sb.WriteRune(r)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Parse the text and romanize it; see RomanizeSyllables.
+func Romanize(text string, scheme RomanizationScheme) string {
+	var sp SyllableParser
+	syllables := sp.ParseGStackClusters(
+		NewGStackClusterParser().ParseGraphemeStacks(ParseGraphemeStacks(text)))
+	return RomanizeSyllables(syllables, scheme)
+}
+
+var romanizationSchemes = map[string]RomanizationScheme{
+	RTGS.Name:    RTGS,
+	Paiboon.Name: Paiboon,
+	IPA.Name:     IPA,
+}
+var romanizationSchemesMutex sync.RWMutex
+
+// Make a scheme available by name, to LookupRomanizationScheme.
+// It is an error to register the same name twice; to change a
+// scheme, use ReplaceRomanizationScheme.
+func RegisterRomanizationScheme(name string, scheme RomanizationScheme) error {
+	romanizationSchemesMutex.Lock()
+	defer romanizationSchemesMutex.Unlock()
+	if _, has := romanizationSchemes[name]; has {
+		return fmt.Errorf("Romanization scheme %s is already registered", name)
+	}
+	romanizationSchemes[name] = scheme
+	return nil
+}
+
+// Register a scheme by name, replacing any scheme that was registered
+// with that name before
+func ReplaceRomanizationScheme(name string, scheme RomanizationScheme) {
+	romanizationSchemesMutex.Lock()
+	defer romanizationSchemesMutex.Unlock()
+	romanizationSchemes[name] = scheme
+}
+
+// Remove a registered scheme; this is for tests
+func unregisterRomanizationScheme(name string) {
+	romanizationSchemesMutex.Lock()
+	defer romanizationSchemesMutex.Unlock()
+	delete(romanizationSchemes, name)
+}
+
+// Find a registered scheme. RTGS, Paiboon+, and IPA are always
+// registered.
+func LookupRomanizationScheme(name string) (RomanizationScheme, bool) {
+	romanizationSchemesMutex.RLock()
+	defer romanizationSchemesMutex.RUnlock()
+	scheme, has := romanizationSchemes[name]
+	return scheme, has
+}
+
+// The names of the registered schemes, sorted
+func RomanizationSchemeNames() []string {
+	romanizationSchemesMutex.RLock()
+	defer romanizationSchemesMutex.RUnlock()
+	names := make([]string, 0, len(romanizationSchemes))
+	for name := range romanizationSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
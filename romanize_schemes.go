@@ -0,0 +1,289 @@
+package paasaathai
+
+// The combining diacritics that the tables use
+const (
+	combiningGrave      = "\u0300"
+	combiningAcute      = "\u0301"
+	combiningCircumflex = "\u0302"
+	combiningMacron     = "\u0304"
+	combiningCaron      = "\u030C"
+	combiningNoRelease  = "\u031A"
+)
+
+// Paiboon+, from "Thai for Beginners" by Benjawan Poomsan Becker.
+// Long vowels are written doubled, and tones are marked with
+// diacritics: à low, â falling, á high, ǎ rising, and mid unmarked.
+// Syllables are separated by hyphens.
+var Paiboon = &TableRomanizationScheme{
+	Name: "Paiboon+",
+	Initials: map[rune]string{
+		THAI_CHARACTER_KO_KAI:         "g",
+		THAI_CHARACTER_KHO_KHAI:       "k",
+		THAI_CHARACTER_KHO_KHUAT:      "k",
+		THAI_CHARACTER_KHO_KHWAI:      "k",
+		THAI_CHARACTER_KHO_KHON:       "k",
+		THAI_CHARACTER_KHO_RAKHANG:    "k",
+		THAI_CHARACTER_NGO_NGU:        "ng",
+		THAI_CHARACTER_CHO_CHAN:       "j",
+		THAI_CHARACTER_CHO_CHING:      "ch",
+		THAI_CHARACTER_CHO_CHANG:      "ch",
+		THAI_CHARACTER_SO_SO:          "s",
+		THAI_CHARACTER_CHO_CHOE:       "ch",
+		THAI_CHARACTER_YO_YING:        "y",
+		THAI_CHARACTER_DO_CHADA:       "d",
+		THAI_CHARACTER_TO_PATAK:       "dt",
+		THAI_CHARACTER_THO_THAN:       "t",
+		THAI_CHARACTER_THO_NANGMONTHO: "t",
+		THAI_CHARACTER_THO_PHUTHAO:    "t",
+		THAI_CHARACTER_NO_NEN:         "n",
+		THAI_CHARACTER_DO_DEK:         "d",
+		THAI_CHARACTER_TO_TAO:         "dt",
+		THAI_CHARACTER_THO_THUNG:      "t",
+		THAI_CHARACTER_THO_THAHAN:     "t",
+		THAI_CHARACTER_THO_THONG:      "t",
+		THAI_CHARACTER_NO_NU:          "n",
+		THAI_CHARACTER_BO_BAIMAI:      "b",
+		THAI_CHARACTER_PO_PLA:         "bp",
+		THAI_CHARACTER_PHO_PHUNG:      "p",
+		THAI_CHARACTER_FO_FA:          "f",
+		THAI_CHARACTER_PHO_PHAN:       "p",
+		THAI_CHARACTER_FO_FAN:         "f",
+		THAI_CHARACTER_PHO_SAMPHAO:    "p",
+		THAI_CHARACTER_MO_MA:          "m",
+		THAI_CHARACTER_YO_YAK:         "y",
+		THAI_CHARACTER_RO_RUA:         "r",
+		THAI_CHARACTER_LO_LING:        "l",
+		THAI_CHARACTER_WO_WAEN:        "w",
+		THAI_CHARACTER_SO_SALA:        "s",
+		THAI_CHARACTER_SO_RUSI:        "s",
+		THAI_CHARACTER_SO_SUA:         "s",
+		THAI_CHARACTER_HO_HIP:         "h",
+		THAI_CHARACTER_LO_CHULA:       "l",
+		THAI_CHARACTER_O_ANG:          "",
+		THAI_CHARACTER_HO_NOKHUK:      "h",
+		// The vowel includes the consonant sound
+		THAI_CHARACTER_RU: "",
+		THAI_CHARACTER_LU: "",
+	},
+	Finals: map[rune]string{
+		THAI_CHARACTER_KO_KAI:         "k",
+		THAI_CHARACTER_KHO_KHAI:       "k",
+		THAI_CHARACTER_KHO_KHUAT:      "k",
+		THAI_CHARACTER_KHO_KHWAI:      "k",
+		THAI_CHARACTER_KHO_KHON:       "k",
+		THAI_CHARACTER_KHO_RAKHANG:    "k",
+		THAI_CHARACTER_NGO_NGU:        "ng",
+		THAI_CHARACTER_CHO_CHAN:       "t",
+		THAI_CHARACTER_CHO_CHANG:      "t",
+		THAI_CHARACTER_SO_SO:          "t",
+		THAI_CHARACTER_CHO_CHOE:       "t",
+		THAI_CHARACTER_YO_YING:        "n",
+		THAI_CHARACTER_DO_CHADA:       "t",
+		THAI_CHARACTER_TO_PATAK:       "t",
+		THAI_CHARACTER_THO_THAN:       "t",
+		THAI_CHARACTER_THO_NANGMONTHO: "t",
+		THAI_CHARACTER_THO_PHUTHAO:    "t",
+		THAI_CHARACTER_NO_NEN:         "n",
+		THAI_CHARACTER_DO_DEK:         "t",
+		THAI_CHARACTER_TO_TAO:         "t",
+		THAI_CHARACTER_THO_THUNG:      "t",
+		THAI_CHARACTER_THO_THAHAN:     "t",
+		THAI_CHARACTER_THO_THONG:      "t",
+		THAI_CHARACTER_NO_NU:          "n",
+		THAI_CHARACTER_BO_BAIMAI:      "p",
+		THAI_CHARACTER_PO_PLA:         "p",
+		THAI_CHARACTER_PHO_PHAN:       "p",
+		THAI_CHARACTER_FO_FAN:         "p",
+		THAI_CHARACTER_PHO_SAMPHAO:    "p",
+		THAI_CHARACTER_MO_MA:          "m",
+		THAI_CHARACTER_YO_YAK:         "i",
+		THAI_CHARACTER_RO_RUA:         "n",
+		THAI_CHARACTER_LO_LING:        "n",
+		THAI_CHARACTER_WO_WAEN:        "o",
+		THAI_CHARACTER_SO_SALA:        "t",
+		THAI_CHARACTER_SO_RUSI:        "t",
+		THAI_CHARACTER_SO_SUA:         "t",
+		THAI_CHARACTER_LO_CHULA:       "n",
+	},
+	Vowels: map[Vowel]string{
+		VowelA:    "a",
+		VowelAA:   "aa",
+		VowelI:    "i",
+		VowelII:   "ii",
+		VowelUE:   "ʉ",
+		VowelUEE:  "ʉʉ",
+		VowelU:    "u",
+		VowelUU:   "uu",
+		VowelE:    "e",
+		VowelEE:   "ee",
+		VowelAE:   "ɛ",
+		VowelAEE:  "ɛɛ",
+		VowelO:    "o",
+		VowelOO:   "oo",
+		VowelAW:   "ɔ",
+		VowelAAW:  "ɔɔ",
+		VowelOE:   "ə",
+		VowelOEE:  "əə",
+		VowelIA:   "ia",
+		VowelIAA:  "iia",
+		VowelUEA:  "ʉa",
+		VowelUEAA: "ʉʉa",
+		VowelUA:   "ua",
+		VowelUAA:  "uua",
+		VowelAM:   "am",
+		VowelAI:   "ai",
+		VowelAO:   "ao",
+		VowelRUE:  "rʉ",
+		VowelRUEE: "rʉʉ",
+		VowelLUE:  "lʉ",
+		VowelLUEE: "lʉʉ",
+	},
+	Tones: map[Tone]string{
+		LowTone:     combiningGrave,
+		FallingTone: combiningCircumflex,
+		HighTone:    combiningAcute,
+		RisingTone:  combiningCaron,
+	},
+	Separator:       "-",
+	RepeatSeparator: "-",
+}
+
+// A broad IPA transcription, in the usual conventions for Thai.
+// Long vowels are marked with ː, tones with diacritics (ā mid, à low,
+// â falling, á high, ǎ rising), and final stops as unreleased.
+// Syllables are separated by periods.
+var IPA = &TableRomanizationScheme{
+	Name: "IPA",
+	Initials: map[rune]string{
+		THAI_CHARACTER_KO_KAI:         "k",
+		THAI_CHARACTER_KHO_KHAI:       "kʰ",
+		THAI_CHARACTER_KHO_KHUAT:      "kʰ",
+		THAI_CHARACTER_KHO_KHWAI:      "kʰ",
+		THAI_CHARACTER_KHO_KHON:       "kʰ",
+		THAI_CHARACTER_KHO_RAKHANG:    "kʰ",
+		THAI_CHARACTER_NGO_NGU:        "ŋ",
+		THAI_CHARACTER_CHO_CHAN:       "tɕ",
+		THAI_CHARACTER_CHO_CHING:      "tɕʰ",
+		THAI_CHARACTER_CHO_CHANG:      "tɕʰ",
+		THAI_CHARACTER_SO_SO:          "s",
+		THAI_CHARACTER_CHO_CHOE:       "tɕʰ",
+		THAI_CHARACTER_YO_YING:        "j",
+		THAI_CHARACTER_DO_CHADA:       "d",
+		THAI_CHARACTER_TO_PATAK:       "t",
+		THAI_CHARACTER_THO_THAN:       "tʰ",
+		THAI_CHARACTER_THO_NANGMONTHO: "tʰ",
+		THAI_CHARACTER_THO_PHUTHAO:    "tʰ",
+		THAI_CHARACTER_NO_NEN:         "n",
+		THAI_CHARACTER_DO_DEK:         "d",
+		THAI_CHARACTER_TO_TAO:         "t",
+		THAI_CHARACTER_THO_THUNG:      "tʰ",
+		THAI_CHARACTER_THO_THAHAN:     "tʰ",
+		THAI_CHARACTER_THO_THONG:      "tʰ",
+		THAI_CHARACTER_NO_NU:          "n",
+		THAI_CHARACTER_BO_BAIMAI:      "b",
+		THAI_CHARACTER_PO_PLA:         "p",
+		THAI_CHARACTER_PHO_PHUNG:      "pʰ",
+		THAI_CHARACTER_FO_FA:          "f",
+		THAI_CHARACTER_PHO_PHAN:       "pʰ",
+		THAI_CHARACTER_FO_FAN:         "f",
+		THAI_CHARACTER_PHO_SAMPHAO:    "pʰ",
+		THAI_CHARACTER_MO_MA:          "m",
+		THAI_CHARACTER_YO_YAK:         "j",
+		THAI_CHARACTER_RO_RUA:         "r",
+		THAI_CHARACTER_LO_LING:        "l",
+		THAI_CHARACTER_WO_WAEN:        "w",
+		THAI_CHARACTER_SO_SALA:        "s",
+		THAI_CHARACTER_SO_RUSI:        "s",
+		THAI_CHARACTER_SO_SUA:         "s",
+		THAI_CHARACTER_HO_HIP:         "h",
+		THAI_CHARACTER_LO_CHULA:       "l",
+		THAI_CHARACTER_O_ANG:          "ʔ",
+		THAI_CHARACTER_HO_NOKHUK:      "h",
+		// The vowel includes the consonant sound
+		THAI_CHARACTER_RU: "",
+		THAI_CHARACTER_LU: "",
+	},
+	Finals: map[rune]string{
+		THAI_CHARACTER_KO_KAI:         "k" + combiningNoRelease,
+		THAI_CHARACTER_KHO_KHAI:       "k" + combiningNoRelease,
+		THAI_CHARACTER_KHO_KHUAT:      "k" + combiningNoRelease,
+		THAI_CHARACTER_KHO_KHWAI:      "k" + combiningNoRelease,
+		THAI_CHARACTER_KHO_KHON:       "k" + combiningNoRelease,
+		THAI_CHARACTER_KHO_RAKHANG:    "k" + combiningNoRelease,
+		THAI_CHARACTER_NGO_NGU:        "ŋ",
+		THAI_CHARACTER_CHO_CHAN:       "t" + combiningNoRelease,
+		THAI_CHARACTER_CHO_CHANG:      "t" + combiningNoRelease,
+		THAI_CHARACTER_SO_SO:          "t" + combiningNoRelease,
+		THAI_CHARACTER_CHO_CHOE:       "t" + combiningNoRelease,
+		THAI_CHARACTER_YO_YING:        "n",
+		THAI_CHARACTER_DO_CHADA:       "t" + combiningNoRelease,
+		THAI_CHARACTER_TO_PATAK:       "t" + combiningNoRelease,
+		THAI_CHARACTER_THO_THAN:       "t" + combiningNoRelease,
+		THAI_CHARACTER_THO_NANGMONTHO: "t" + combiningNoRelease,
+		THAI_CHARACTER_THO_PHUTHAO:    "t" + combiningNoRelease,
+		THAI_CHARACTER_NO_NEN:         "n",
+		THAI_CHARACTER_DO_DEK:         "t" + combiningNoRelease,
+		THAI_CHARACTER_TO_TAO:         "t" + combiningNoRelease,
+		THAI_CHARACTER_THO_THUNG:      "t" + combiningNoRelease,
+		THAI_CHARACTER_THO_THAHAN:     "t" + combiningNoRelease,
+		THAI_CHARACTER_THO_THONG:      "t" + combiningNoRelease,
+		THAI_CHARACTER_NO_NU:          "n",
+		THAI_CHARACTER_BO_BAIMAI:      "p" + combiningNoRelease,
+		THAI_CHARACTER_PO_PLA:         "p" + combiningNoRelease,
+		THAI_CHARACTER_PHO_PHAN:       "p" + combiningNoRelease,
+		THAI_CHARACTER_FO_FAN:         "p" + combiningNoRelease,
+		THAI_CHARACTER_PHO_SAMPHAO:    "p" + combiningNoRelease,
+		THAI_CHARACTER_MO_MA:          "m",
+		THAI_CHARACTER_YO_YAK:         "j",
+		THAI_CHARACTER_RO_RUA:         "n",
+		THAI_CHARACTER_LO_LING:        "n",
+		THAI_CHARACTER_WO_WAEN:        "w",
+		THAI_CHARACTER_SO_SALA:        "t" + combiningNoRelease,
+		THAI_CHARACTER_SO_RUSI:        "t" + combiningNoRelease,
+		THAI_CHARACTER_SO_SUA:         "t" + combiningNoRelease,
+		THAI_CHARACTER_LO_CHULA:       "n",
+	},
+	Vowels: map[Vowel]string{
+		VowelA:    "a",
+		VowelAA:   "aː",
+		VowelI:    "i",
+		VowelII:   "iː",
+		VowelUE:   "ɯ",
+		VowelUEE:  "ɯː",
+		VowelU:    "u",
+		VowelUU:   "uː",
+		VowelE:    "e",
+		VowelEE:   "eː",
+		VowelAE:   "ɛ",
+		VowelAEE:  "ɛː",
+		VowelO:    "o",
+		VowelOO:   "oː",
+		VowelAW:   "ɔ",
+		VowelAAW:  "ɔː",
+		VowelOE:   "ɤ",
+		VowelOEE:  "ɤː",
+		VowelIA:   "ia",
+		VowelIAA:  "iːa",
+		VowelUEA:  "ɯa",
+		VowelUEAA: "ɯːa",
+		VowelUA:   "ua",
+		VowelUAA:  "uːa",
+		VowelAM:   "am",
+		VowelAI:   "aj",
+		VowelAO:   "aw",
+		VowelRUE:  "rɯ",
+		VowelRUEE: "rɯː",
+		VowelLUE:  "lɯ",
+		VowelLUEE: "lɯː",
+	},
+	Tones: map[Tone]string{
+		MidTone:     combiningMacron,
+		LowTone:     combiningGrave,
+		FallingTone: combiningCircumflex,
+		HighTone:    combiningAcute,
+		RisingTone:  combiningCaron,
+	},
+	OpenShortFinal:  "ʔ",
+	Separator:       ".",
+	RepeatSeparator: ".",
+}
@@ -0,0 +1,116 @@
+package paasaathai
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestRomanizeRTGS(c *C) {
+	words := []struct {
+		thai  string
+		roman string
+	}{
+		{"สวัสดี", "sawatdi"},
+		{"ประเทศไทย", "prathetthai"},
+		{"ข้าว", "khao"},
+		{"กรุงเทพ", "krungthep"},
+		{"เด็ก", "dek"},
+		{"จันทร์", "chan"},
+		{"ภาษาไทย", "phasathai"},
+		{"เลย", "loei"},
+		{"เมือง", "mueang"},
+		{"ขอบคุณ", "khopkhun"},
+		{"หลัง", "lang"},
+		{"สร้าง", "sang"},
+	}
+	for _, w := range words {
+		c.Check(Romanize(w.thai, RTGS), Equals, w.roman, Commentf("%s", w.thai))
+	}
+}
+
+func (s *MySuite) TestRomanizePassThrough(c *C) {
+	c.Check(Romanize("ปี ๒๕๖๙", RTGS), Equals, "pi 2569")
+	c.Check(Romanize("เด็กๆ", RTGS), Equals, "dek dek")
+	c.Check(Romanize("OK ไป", RTGS), Equals, "OK pai")
+}
+
+func (s *MySuite) TestRomanizePaiboon(c *C) {
+	words := []struct {
+		thai  string
+		roman string
+	}{
+		{"สบาย", "sà-baai"},
+		{"ข้าว", "kâao"},
+		{"ไทย", "tai"},
+		{"ปลา", "bplaa"},
+		{"เลย", "ləəi"},
+		{"ขอบคุณ", "kɔ̀ɔp-kun"},
+		{"น้ำ", "nám"},
+		{"หมา", "mǎa"},
+		{"เมือง", "mʉʉang"},
+	}
+	for _, w := range words {
+		c.Check(Romanize(w.thai, Paiboon), Equals, w.roman, Commentf("%s", w.thai))
+	}
+	c.Check(Romanize("เด็กๆ", Paiboon), Equals, "dèk-dèk")
+}
+
+func (s *MySuite) TestRomanizeIPA(c *C) {
+	words := []struct {
+		thai  string
+		roman string
+	}{
+		{"สบาย", "sàʔ.bāːj"},
+		{"ข้าว", "kʰâːw"},
+		{"ไทย", "tʰāj"},
+		{"อยู่", "jùː"},
+		{"เด็ก", "dèk̚"},
+	}
+	for _, w := range words {
+		c.Check(Romanize(w.thai, IPA), Equals, w.roman, Commentf("%s", w.thai))
+	}
+	c.Check(Romanize("เด็กๆ", IPA), Equals, "dèk̚.dèk̚")
+}
+
+type upperScheme struct{}
+
+func (s upperScheme) RomanizeSyllable(syllable *Syllable) string {
+	return strings.ToUpper(RTGS.RomanizeSyllable(syllable))
+}
+
+func (s upperScheme) SyllableSeparator() string {
+	return " "
+}
+
+func (s upperScheme) MaiYamokSeparator() string {
+	return " "
+}
+
+func (s *MySuite) TestRegisterRomanizationScheme(c *C) {
+	scheme, has := LookupRomanizationScheme("Paiboon+")
+	c.Assert(has, Equals, true)
+	c.Check(scheme, Equals, Paiboon)
+
+	err := RegisterRomanizationScheme("RTGS", upperScheme{})
+	c.Check(err, NotNil)
+
+	err = RegisterRomanizationScheme("test-upper", upperScheme{})
+	c.Assert(err, IsNil)
+	defer unregisterRomanizationScheme("test-upper")
+	scheme, has = LookupRomanizationScheme("test-upper")
+	c.Assert(has, Equals, true)
+	c.Check(Romanize("สวัสดี", scheme), Equals, "SA WAT DI")
+	c.Check(RomanizationSchemeNames(), DeepEquals,
+		[]string{"IPA", "Paiboon+", "RTGS", "test-upper"})
+}
+
+func (s *MySuite) TestReplaceRomanizationScheme(c *C) {
+	ReplaceRomanizationScheme("test-replace", RTGS)
+	defer unregisterRomanizationScheme("test-replace")
+	ReplaceRomanizationScheme("test-replace", upperScheme{})
+
+	scheme, has := LookupRomanizationScheme("test-replace")
+	c.Assert(has, Equals, true)
+	c.Check(Romanize("เด็กๆ", scheme), Equals, "DEK DEK")
+}
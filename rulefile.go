@@ -0,0 +1,279 @@
+package paasaathai
+
+// Rules can be written in a file, instead of in Go, and loaded when the
+// program runs:
+//
+//	# Comments start with #
+//	rule sara_uea_final
+//		pattern ([:sara e:]) ([:consonant: && :sara uee:])
+//		pattern ([:o ang:]) ([:consonant:])
+//		front_vowel 1
+//		first_consonant 2
+//		tail 3 4
+//		before sandwich_ueea_er
+//
+// Each rule starts with "rule" and its name, and the lines after it, up
+// to the next rule, describe it:
+//
+//	pattern          the objregexp pattern; more pattern lines are
+//	                 joined to it, with spaces
+//	front_vowel      the group that is the FrontVowel
+//	first_consonant  the group that is the FirstConsonant
+//	single_mid_sign  the group that is the SingleMidSign
+//	invalid_thai     the group that is the InvalidThai
+//	tail             the groups that are the Tail, in order
+//	invalid          the InvalidReason, as in SoloDiacritic, which
+//	                 makes the clusters of the rule invalid
+//	before, after    the names of the rules that this rule must be
+//	                 tried before, or after
+//
+// Groups are numbered from 1, in the order of their left parentheses,
+// or named, as in (?P<tail>...), if the name has no spaces. A group for
+// one of the single fields gives it the first stack of the group; a
+// group that matched nothing leaves the field unset.
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gilramir/objregexp"
+)
+
+// A rule as it is read from a rule file
+type ruleFileRule struct {
+	name    string
+	line    int
+	pattern []string
+
+	// The group of each single field, by directive name
+	fields map[string]string
+	tail   []string
+
+	invalid InvalidReason
+	before  []string
+	after   []string
+}
+
+// The directives which set a field to one stack
+var ruleFileFields = []string{"front_vowel", "first_consonant", "single_mid_sign", "invalid_thai"}
+
+// Read rules in the rule file format. The rules can be given to
+// GStackClusterParser.AddRule.
+func ReadTccRules(r io.Reader) ([]*TccRule, error) {
+	var specs []*ruleFileRule
+	var spec *ruleFileRule
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		directive, arg := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			directive, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		args := strings.Fields(arg)
+		fail := func(format string, a ...interface{}) ([]*TccRule, error) {
+			return nil, fmt.Errorf("line %d: %s", lineNum, fmt.Sprintf(format, a...))
+		}
+
+		if directive == "rule" {
+			if len(args) != 1 {
+				return fail("rule needs one name")
+			}
+			spec = &ruleFileRule{
+				name:   args[0],
+				line:   lineNum,
+				fields: make(map[string]string),
+			}
+			specs = append(specs, spec)
+			continue
+		}
+		if spec == nil {
+			return fail("%s is not in a rule", directive)
+		}
+
+		switch directive {
+		case "pattern":
+			if arg == "" {
+				return fail("pattern is empty")
+			}
+			spec.pattern = append(spec.pattern, arg)
+
+		case "front_vowel", "first_consonant", "single_mid_sign", "invalid_thai":
+			if len(args) != 1 {
+				return fail("%s needs one group", directive)
+			}
+			if _, has := spec.fields[directive]; has {
+				return fail("%s is given more than once", directive)
+			}
+			spec.fields[directive] = args[0]
+
+		case "tail":
+			if len(args) == 0 {
+				return fail("tail needs at least one group")
+			}
+			spec.tail = append(spec.tail, args...)
+
+		case "invalid":
+			if len(args) != 1 {
+				return fail("invalid needs one reason")
+			}
+			if err := spec.invalid.UnmarshalText([]byte(args[0])); err != nil {
+				return fail("%s", err)
+			}
+			if spec.invalid == NoInvalidReason {
+				return fail("invalid needs a reason other than NoInvalidReason")
+			}
+
+		case "before", "after":
+			if len(args) == 0 {
+				return fail("%s needs at least one rule name", directive)
+			}
+			if directive == "before" {
+				spec.before = append(spec.before, args...)
+			} else {
+				spec.after = append(spec.after, args...)
+			}
+
+		default:
+			return fail("unknown directive %q", directive)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The rules are compiled with the classes that every parser has
+	var compiler objregexp.Compiler[GraphemeStack]
+	initializeTccCompiler(&compiler)
+
+	rules := make([]*TccRule, 0, len(specs))
+	for _, spec := range specs {
+		rule, err := spec.tccRule(&compiler)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: rule %s: %w", spec.line, spec.name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Read rules from a file; see ReadTccRules
+func LoadTccRuleFile(filename string) ([]*TccRule, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	rules, err := ReadTccRules(fh)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// Add the rules of a rule file, as AddRule does. This must be called
+// before Initialize; it returns ParserAlreadyInitializedError after
+// that.
+func (s *GStackClusterParser) AddRuleFile(filename string) error {
+	if s.initialized {
+		return ParserAlreadyInitializedError
+	}
+	rules, err := LoadTccRuleFile(filename)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		s.AddRule(rule)
+	}
+	return nil
+}
+
+// Make the rule, checking that its pattern compiles, and that its
+// groups are in its pattern
+func (s *ruleFileRule) tccRule(compiler *objregexp.Compiler[GraphemeStack]) (*TccRule, error) {
+	if len(s.pattern) == 0 {
+		return nil, fmt.Errorf("it has no pattern")
+	}
+	pattern := strings.Join(s.pattern, " ")
+
+	if _, err := compiler.Compile(pattern); err != nil {
+		return nil, err
+	}
+	numGroups, groupNames := patternGroups(pattern)
+
+	// Find the number of each group
+	group := func(g string) (int, error) {
+		if n, err := strconv.Atoi(g); err == nil {
+			if n < 1 || n > numGroups {
+				return 0, fmt.Errorf("the pattern has no group %d", n)
+			}
+			return n, nil
+		}
+		n, has := groupNames[g]
+		if !has {
+			return 0, fmt.Errorf("the pattern has no group named %s", g)
+		}
+		return n, nil
+	}
+
+	type fieldGroup struct {
+		field string
+		n     int
+	}
+	var fields []fieldGroup
+	for _, field := range ruleFileFields {
+		if g, has := s.fields[field]; has {
+			n, err := group(g)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, fieldGroup{field, n})
+		}
+	}
+	for _, g := range s.tail {
+		n, err := group(g)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fieldGroup{"tail", n})
+	}
+
+	invalid := s.invalid
+	extract := func(m objregexp.Match, input []GraphemeStack, i int, c *GStackCluster) int {
+		*c = NewGStackCluster(input[i : i+m.Length()])
+		for _, f := range fields {
+			reg := m.Group(f.n)
+			if reg.Start < 0 || reg.Empty() {
+				continue
+			}
+			switch f.field {
+			case "front_vowel":
+				c.FrontVowel = input[reg.Start]
+			case "first_consonant":
+				c.FirstConsonant = input[reg.Start]
+			case "single_mid_sign":
+				c.SingleMidSign = input[reg.Start]
+			case "invalid_thai":
+				c.InvalidThai = input[reg.Start]
+			case "tail":
+				c.Tail = append(c.Tail, input[reg.Start:reg.End]...)
+			}
+		}
+		if invalid != NoInvalidReason {
+			c.IsValidThai = false
+			c.InvalidReason = invalid
+		}
+		return m.Length()
+	}
+
+	return NewTccRule(s.name, pattern, extract).Before(s.before...).After(s.after...), nil
+}
@@ -0,0 +1,116 @@
+package paasaathai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+const testRuleFile = `
+# เ-ือ with a final consonant is one cluster
+rule sara_uea_final
+	pattern ([:sara e:]) ([:consonant: && :sara uee:])
+	pattern ([:o ang:]) (?P<final>[:consonant:])
+	front_vowel 1
+	first_consonant 2
+	tail 3 final
+	before sandwich_ueea_er
+
+rule lone_lakkhangyao
+	pattern [:lakkhangyao:]
+	invalid NonModernThai
+	after error_short_o_ang
+`
+
+func (s *MySuite) TestReadTccRules(c *C) {
+	rules, err := ReadTccRules(strings.NewReader(testRuleFile))
+	c.Assert(err, IsNil)
+	c.Assert(rules, HasLen, 2)
+	c.Check(rules[0].Name(), Equals, "sara_uea_final")
+	c.Check(rules[0].Pattern(), Equals,
+		"([:sara e:]) ([:consonant: && :sara uee:]) ([:o ang:]) (?P<final>[:consonant:])")
+
+	var gcp GStackClusterParser
+	for _, rule := range rules {
+		gcp.AddRule(rule)
+	}
+	c.Assert(gcp.Initialize(), IsNil)
+
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("เกือกๅ"))
+	c.Assert(clusters, HasLen, 2)
+	cc := clusters[0]
+	c.Check(cc.Text, Equals, "เกือก")
+	c.Check(cc.MatchingRule, Equals, "sara_uea_final")
+	c.Check(cc.IsValidThai, Equals, true)
+	c.Check(cc.FrontVowel.Text, Equals, "เ")
+	c.Check(cc.FirstConsonant.Text, Equals, "กื")
+	c.Assert(cc.Tail, HasLen, 2)
+	c.Check(cc.Tail[0].Text, Equals, "อ")
+	c.Check(cc.Tail[1].Text, Equals, "ก")
+
+	c.Check(clusters[1].MatchingRule, Equals, "lone_lakkhangyao")
+	c.Check(clusters[1].IsValidThai, Equals, false)
+	c.Check(clusters[1].InvalidReason, Equals, ReasonNonModernThai)
+}
+
+// The groups are numbered as objregexp numbers them
+func (s *MySuite) TestReadTccRulesNestedGroups(c *C) {
+	rules, err := ReadTccRules(strings.NewReader("rule pair\n" +
+		"\tpattern (?P<pair>([:consonant:]) ([:consonant:]))\n" +
+		"\tfirst_consonant 3\n\ttail pair\n\tbefore consonant\n"))
+	c.Assert(err, IsNil)
+
+	var gcp GStackClusterParser
+	gcp.AddRule(rules[0])
+	c.Assert(gcp.Initialize(), IsNil)
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("คง"))
+	c.Assert(clusters, HasLen, 1)
+	c.Check(clusters[0].MatchingRule, Equals, "pair")
+	c.Check(clusters[0].FirstConsonant.Text, Equals, "ง")
+	c.Check(clusters[0].Tail, HasLen, 2)
+}
+
+func (s *MySuite) TestAddRuleFile(c *C) {
+	filename := filepath.Join(c.MkDir(), "rules.txt")
+	c.Assert(os.WriteFile(filename, []byte(testRuleFile), 0644), IsNil)
+
+	var gcp GStackClusterParser
+	c.Assert(gcp.AddRuleFile(filename), IsNil)
+	c.Assert(gcp.Initialize(), IsNil)
+	c.Check(gcp.RuleNames(), Not(HasLen), 0)
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("เกือก"))
+	c.Check(clusters[0].MatchingRule, Equals, "sara_uea_final")
+	c.Check(gcp.AddRuleFile(filename), Equals, ParserAlreadyInitializedError)
+
+	c.Assert(os.WriteFile(filename, []byte("rule x\n\tfront_vowel 1\n"), 0644), IsNil)
+	gcp = GStackClusterParser{}
+	c.Check(gcp.AddRuleFile(filename), ErrorMatches,
+		".*rules.txt: line 1: rule x: it has no pattern")
+}
+
+func (s *MySuite) TestReadTccRulesErrors(c *C) {
+	for _, t := range []struct {
+		text string
+		err  string
+	}{
+		{"pattern [:consonant:]", "line 1: pattern is not in a rule"},
+		{"rule a b", "line 1: rule needs one name"},
+		{"rule a\n  colour red", `line 2: unknown directive "colour"`},
+		{"rule a\n  invalid Bad", `line 2: unknown InvalidReason "Bad"`},
+		{"rule a\n  invalid NoInvalidReason", "line 2: invalid needs a reason other than NoInvalidReason"},
+		{"rule a\n  pattern ([:consonant:])\n  tail 2", "line 1: rule a: the pattern has no group 2"},
+		{"rule a\n  pattern ([:consonant:])\n  tail x", "line 1: rule a: the pattern has no group named x"},
+		{"rule a\n  pattern ([:consonant:])\n  first_consonant 1\n  first_consonant 1",
+			"line 4: first_consonant is given more than once"},
+		{"rule a\n  before", "line 2: before needs at least one rule name"},
+		{"rule a\n  pattern ([:consonant:]", "line 1: rule a: Parsing objregexp: .*"},
+		{"rule a\n  pattern [:no such class:]", "line 1: rule a: .*"},
+		{"rule a\n  pattern [:consonant: && (:sara uee:)]\n  tail 1",
+			"line 1: rule a: the pattern has no group 1"},
+	} {
+		_, err := ReadTccRules(strings.NewReader(t.text))
+		c.Check(err, ErrorMatches, t.err, Commentf("%s", t.text))
+	}
+}
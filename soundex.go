@@ -0,0 +1,279 @@
+package paasaathai
+
+// Phonetic keys for Thai, so that words which are spelled differently
+// but sound alike, like names written with ศ, ษ, or ส, have the same
+// key.
+//
+// LK82 and Udom83 follow the descriptions in
+// "Thai Soundex" by Vichit Lorchirachoonkul (1982), and
+// "Thai Soundex" by Jaruwan Udompanich (1983), as they are commonly
+// implemented. MetaSound follows "A Novel Thai Soundex Algorithm" by
+// Chakkrit Snae and Michael Brückner (2009), except that the first
+// letter is replaced by the letter that is usually written for its
+// sound. Keys from other implementations may differ in edge cases.
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Map each rune of from to the rune at the same position in to
+func soundexTable(from string, to string) map[rune]rune {
+	f, t := []rune(from), []rune(to)
+	if len(f) != len(t) {
+		panic("soundex table lengths differ")
+	}
+	table := make(map[rune]rune, len(f))
+	for i, r := range f {
+		table[r] = t[i]
+	}
+	return table
+}
+
+// The silent consonant pairs which a thanthakhat marks, as in จันทร์
+var karanPairs = NewSetFromSlice([]string{"จน", "มณ", "ณฑ", "ทร", "ตร"})
+
+// The stacks of the text which are pronounced. A stack with a
+// thanthakhat is silent, and so is the consonant before it if they are
+// one of the karanPairs.
+func pronouncedGraphemeStacks(text string) []GraphemeStack {
+	gstacks := ParseGraphemeStacks(text)
+	pronounced := make([]GraphemeStack, 0, len(gstacks))
+	for _, gs := range gstacks {
+		if gs.UpperDiacritic != THAI_CHARACTER_THANTHAKHAT {
+			pronounced = append(pronounced, gs)
+			continue
+		}
+		if n := len(pronounced); n > 0 &&
+			karanPairs.Has(string([]rune{pronounced[n-1].Main, gs.Main})) {
+			pronounced = pronounced[:n-1]
+		}
+	}
+	return pronounced
+}
+
+// The code points of the stacks, without the tone marks and the signs
+// which have no sound
+func soundexRunes(gstacks []GraphemeStack) []rune {
+	runes := make([]rune, 0, len(gstacks)*2)
+	for _, gs := range gstacks {
+		for _, r := range []rune{gs.Main, gs.DiacriticVowel, gs.UpperDiacritic} {
+			switch {
+			case r == 0, RuneIsToneMark(r),
+				r == THAI_CHARACTER_PAIYANNOI, r == THAI_CHARACTER_PHINTHU,
+				r == THAI_CHARACTER_MAIYAMOK, r == THAI_CHARACTER_MAITAIKHU,
+				r == THAI_CHARACTER_NIKHAHIT, r == THAI_CHARACTER_YAMAKKAN:
+				continue
+			}
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// Pad the key with zeros, or cut it, to the length
+func soundexPad(key []rune, length int) string {
+	for len(key) < length {
+		key = append(key, '0')
+	}
+	return string(key[:length])
+}
+
+var lk82Initials = soundexTable(
+	"กขฃคฅฆงจฉชฌซศษสญยฎดฏตณนฐฑฒถทธบปผพภฝฟมรลฬฤฦวหฮอ",
+	"กกกกกกงจชชชซซซซยยดดตตนนททททททบปพพพฟฟมรรรรรวหหอ")
+
+var lk82Codes = soundexTable(
+	"กขฃคฅฆงจฉชซฌฎฏฐฑฒดตถทธศษสญณนรลฬฤฦบปพฟภผฝมำยวไใหฮาๅึืเแโุูอ",
+	"1111112333333333333333333444444445555555667777889AAABCDEEF")
+
+// The LK82 key of the text: the first consonant, and four codes for the
+// sounds after it, as in ร3000 for รถ and for รด.
+func LK82(text string) string {
+	runes := soundexRunes(pronouncedGraphemeStacks(text))
+	if len(runes) == 0 {
+		return ""
+	}
+
+	// The codes; 0 separates codes which would otherwise be merged
+	var codes []rune
+	appendCode := func(r rune) {
+		if code, has := lk82Codes[r]; has {
+			codes = append(codes, code)
+		}
+	}
+
+	var key []rune
+	if initial, has := lk82Initials[runes[0]]; has {
+		key = append(key, initial)
+		runes = runes[1:]
+	} else {
+		// A front vowel, before the first consonant
+		if len(runes) > 1 {
+			if initial, has := lk82Initials[runes[1]]; has {
+				key = append(key, initial)
+			}
+		}
+		appendCode(runes[0])
+		if len(runes) > 1 {
+			runes = runes[2:]
+		} else {
+			runes = nil
+		}
+	}
+
+	isLongOrRoundVowel := func(i int) bool {
+		if i >= len(runes) {
+			return false
+		}
+		switch runes[i] {
+		case THAI_CHARACTER_SARA_UE, THAI_CHARACTER_SARA_UEE,
+			THAI_CHARACTER_SARA_U, THAI_CHARACTER_SARA_UU:
+			return true
+		}
+		return false
+	}
+
+	lastVowel := -2
+	for i, r := range runes {
+		switch r {
+		// Only separate the codes
+		case THAI_CHARACTER_SARA_A, THAI_CHARACTER_MAI_HAN_AKAT,
+			THAI_CHARACTER_SARA_I, THAI_CHARACTER_SARA_II:
+			lastVowel = i
+			codes = append(codes, 0)
+
+		case THAI_CHARACTER_SARA_AA, THAI_CHARACTER_SARA_UE,
+			THAI_CHARACTER_SARA_UEE, THAI_CHARACTER_SARA_UU,
+			THAI_CHARACTER_LAKKHANGYAO:
+			lastVowel = i
+			appendCode(r)
+
+		case THAI_CHARACTER_SARA_U:
+			lastVowel = i
+			if i > 0 && (runes[i-1] == THAI_CHARACTER_TO_TAO ||
+				runes[i-1] == THAI_CHARACTER_THO_THONG) {
+				codes = append(codes, 0)
+			} else {
+				appendCode(r)
+			}
+
+		// Leading consonants are silent
+		case THAI_CHARACTER_HO_HIP, THAI_CHARACTER_O_ANG:
+			if isLongOrRoundVowel(i + 1) {
+				appendCode(r)
+			}
+
+		// Only pronounced after a vowel, or before one of these
+		case THAI_CHARACTER_YO_YAK, THAI_CHARACTER_RO_RUA, THAI_CHARACTER_RU,
+			THAI_CHARACTER_LO_LING, THAI_CHARACTER_LU, THAI_CHARACTER_WO_WAEN:
+			if lastVowel == i-1 || isLongOrRoundVowel(i+1) {
+				appendCode(r)
+			}
+
+		default:
+			appendCode(r)
+		}
+	}
+
+	// Merge repeated codes, then drop the separators
+	for i, code := range codes {
+		if code != 0 && (i == 0 || code != codes[i-1]) {
+			key = append(key, code)
+		}
+	}
+	if len(key) == 0 {
+		return ""
+	}
+	return soundexPad(key, 5)
+}
+
+var udom83Initials = soundexTable(
+	"กขฃคฅฆงจฉชฌซศษสฎดฏตฐฑฒถทธณนบปผพภฝฟมญยรลฬฤฦวอหฮ",
+	"กขขขขขงจชชชสสสสดดตตททททททนนบปพพพฟฟมยยรรรรรวอฮฮ")
+
+var udom83Codes = soundexTable(
+	"มวำกขฃคฅฆงยญณนฎฏดตศษสบปพภผฝฟหอฮจฉชซฌฐฑฒถทธรฤลฦ",
+	"0001111112233344444445555666666777778888889999")
+
+// Rewrites of the spelling, in order, before the Udom83 codes are found
+var udom83Rewrites = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	// รร is an a vowel, or an a vowel and an n
+	{regexp.MustCompile("รร([เ-ไ])"), "ั$1"},
+	{regexp.MustCompile("รร([ก-ฮ][ก-ฮเ-ไ])"), "ั$1"},
+	{regexp.MustCompile("รร([ก-ฮ][ะ-ู่-์])"), "ัน$1"},
+	{regexp.MustCompile("รร"), "ัน"},
+	// ไ and ใ end in a y
+	{regexp.MustCompile("ไ([ก-ฮ]ย)"), "$1"},
+	{regexp.MustCompile("[ไใ]([ก-ฮ])"), "${1}ย"},
+	// ำ ends in an m
+	{regexp.MustCompile("ำ(ม[ะ-ู])"), "ม$1"},
+	{regexp.MustCompile("ำม"), "ม"},
+	{regexp.MustCompile("ำ"), "ม"},
+	// Then only the consonants are kept
+	{regexp.MustCompile("[ะ-์]"), ""},
+}
+
+// The Udom83 key of the text: the first consonant, and six codes for
+// the consonants after it, as in ร800000 for รถ.
+func Udom83(text string) string {
+	var sb strings.Builder
+	for _, gs := range pronouncedGraphemeStacks(text) {
+		sb.WriteString(gs.Text)
+	}
+	spelling := sb.String()
+	for _, rewrite := range udom83Rewrites {
+		spelling = rewrite.re.ReplaceAllString(spelling, rewrite.replacement)
+	}
+
+	var key []rune
+	for _, r := range spelling {
+		table := udom83Codes
+		if len(key) == 0 {
+			table = udom83Initials
+		}
+		if code, has := table[r]; has {
+			key = append(key, code)
+		}
+	}
+	if len(key) == 0 {
+		return ""
+	}
+	return soundexPad(key, 7)
+}
+
+var metaSoundInitials = soundexTable(
+	"ขฃคฅฆฉชฌซศษสญฎฏฐฑฒถทธณผภฝฤฬฦฮ",
+	"คคคคคชชชสสสสยดตททททททนพพฟรลลห")
+
+var metaSoundCodes = soundexTable(
+	"กขฃคฅฆจฉชซฌฎฏฐฑฒดตถทธศษสบปผพภฝฟงญณนรลฬฤฦมยว",
+	"1111112222222222222222223333333455555555678")
+
+// The MetaSound key of the text: the first consonant, and three codes
+// for the consonants after it, as in บ550 for บูรณะ. The vowels are
+// ignored.
+func MetaSound(text string) string {
+	var key []rune
+	for _, gs := range pronouncedGraphemeStacks(text) {
+		r := gs.Main
+		if !RuneIsConsonant(r) {
+			continue
+		}
+		if len(key) == 0 {
+			if initial, has := metaSoundInitials[r]; has {
+				r = initial
+			}
+			key = append(key, r)
+		} else if code, has := metaSoundCodes[r]; has {
+			key = append(key, code)
+		}
+	}
+	if len(key) == 0 {
+		return ""
+	}
+	return soundexPad(key, 4)
+}
@@ -0,0 +1,51 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestLK82(c *C) {
+	c.Check(LK82("รถ"), Equals, "ร3000")
+	c.Check(LK82("รด"), Equals, "ร3000")
+	c.Check(LK82("จัน"), Equals, "จ4000")
+	// The silent letters don't count
+	c.Check(LK82("จันทร์"), Equals, "จ4000")
+	// The front vowel comes after the first consonant
+	c.Check(LK82("เพชร"), Equals, "พB300")
+	c.Check(LK82(""), Equals, "")
+	c.Check(LK82("abc"), Equals, "")
+}
+
+func (s *MySuite) TestUdom83(c *C) {
+	c.Check(Udom83("รถ"), Equals, "ร800000")
+	c.Check(Udom83("กรรม"), Equals, "ก300000")
+	c.Check(Udom83("ไทย"), Equals, "ท200000")
+	c.Check(Udom83(""), Equals, "")
+}
+
+func (s *MySuite) TestMetaSound(c *C) {
+	c.Check(MetaSound("รักษ์"), Equals, "ร100")
+	c.Check(MetaSound("บูรณะ"), Equals, "บ550")
+	c.Check(MetaSound("บูรณการ"), Equals, "บ551")
+	c.Check(MetaSound("ลักษณะ"), Equals, "ล125")
+	c.Check(MetaSound(""), Equals, "")
+}
+
+func (s *MySuite) TestSoundexVariants(c *C) {
+	// Spellings which sound alike
+	variants := [][]string{
+		{"ศรี", "สรี", "ษรี"},
+		{"ทวี", "ธวี", "ฒวี", "ฑวี"},
+		{"สมศักดิ์", "สมศัก"},
+		{"สุรเชษฐ์", "สุรเชษ"},
+		{"เพชร", "เพ็ชร"},
+	}
+	for _, key := range []func(string) string{LK82, Udom83, MetaSound} {
+		for _, spellings := range variants {
+			for _, spelling := range spellings[1:] {
+				c.Check(key(spelling), Equals, key(spellings[0]),
+					Commentf("%s and %s", spelling, spellings[0]))
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package paasaathai
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A cluster found by RuleStats, with the clusters around it
+type RuleSample struct {
+	// Where the text came from, as given to RuleStats.Add
+	Ref string
+
+	// The cluster, and the text around it, with the cluster in
+	// brackets, as in ส[วัส]ดี
+	Text    string
+	Context string
+}
+
+// How many clusters a rule made, or how many had an InvalidReason
+type RuleCount struct {
+	Count   int
+	Samples []RuleSample
+}
+
+// Counts the clusters that each rule of a parser makes, and the invalid
+// clusters by their InvalidReason, across a corpus. It keeps a few
+// samples of each, and finds the rules which never made a cluster.
+type RuleStats struct {
+	// How many samples to keep for each rule and reason, each of a
+	// different cluster text
+	MaxSamples int
+
+	// How many Thai clusters were counted
+	Clusters int
+
+	Rules   map[string]*RuleCount
+	Reasons map[InvalidReason]*RuleCount
+
+	// The rules of the parser, in order
+	ruleNames []string
+}
+
+// Make a collector for the clusters made by the parser; if it is nil,
+// NewGStackClusterParser is used.
+func NewRuleStats(parser *GStackClusterParser) *RuleStats {
+	if parser == nil {
+		parser = NewGStackClusterParser()
+	}
+	return &RuleStats{
+		MaxSamples: 3,
+		Rules:      make(map[string]*RuleCount),
+		Reasons:    make(map[InvalidReason]*RuleCount),
+		ruleNames:  parser.RuleNames(),
+	}
+}
+
+// The number of clusters around a sample kept in its context
+const ruleSampleContext = 3
+
+// Count the clusters of a text, which were made by the parser. The ref
+// says where the text came from, for the samples. Clusters which are
+// not Thai are not counted.
+func (s *RuleStats) Add(clusters []GStackCluster, ref string) {
+	for i := range clusters {
+		cc := &clusters[i]
+		if !cc.IsThai {
+			continue
+		}
+		s.Clusters++
+		if cc.MatchingRule != "" {
+			rc := s.Rules[cc.MatchingRule]
+			if rc == nil {
+				rc = &RuleCount{}
+				s.Rules[cc.MatchingRule] = rc
+			}
+			rc.add(s.MaxSamples, clusters, i, ref)
+		}
+		if !cc.IsValidThai {
+			rc := s.Reasons[cc.InvalidReason]
+			if rc == nil {
+				rc = &RuleCount{}
+				s.Reasons[cc.InvalidReason] = rc
+			}
+			rc.add(s.MaxSamples, clusters, i, ref)
+		}
+	}
+}
+
+func (s *RuleCount) add(maxSamples int, clusters []GStackCluster, i int, ref string) {
+	s.Count++
+	if len(s.Samples) >= maxSamples {
+		return
+	}
+	text := clusters[i].Text
+	for _, sample := range s.Samples {
+		if sample.Text == text {
+			return
+		}
+	}
+
+	var sb strings.Builder
+	start := i - ruleSampleContext
+	if start < 0 {
+		start = 0
+	}
+	for _, cc := range clusters[start:i] {
+		sb.WriteString(cc.Text)
+	}
+	sb.WriteString("[" + text + "]")
+	end := i + 1 + ruleSampleContext
+	if end > len(clusters) {
+		end = len(clusters)
+	}
+	for _, cc := range clusters[i+1 : end] {
+		sb.WriteString(cc.Text)
+	}
+	s.Samples = append(s.Samples, RuleSample{Ref: ref, Text: text, Context: sb.String()})
+}
+
+// The rules of the parser which never made a cluster, in the order that
+// they are tried
+func (s *RuleStats) Unused() []string {
+	var unused []string
+	for _, name := range s.ruleNames {
+		if _, has := s.Rules[name]; !has {
+			unused = append(unused, name)
+		}
+	}
+	return unused
+}
+
+// Write the counts, most first, with their samples, and the rules that
+// never made a cluster
+func (s *RuleStats) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d Thai clusters\n", s.Clusters)
+
+	names := make([]string, 0, len(s.Rules))
+	for name := range s.Rules {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ci, cj := s.Rules[names[i]].Count, s.Rules[names[j]].Count
+		if ci != cj {
+			return ci > cj
+		}
+		return names[i] < names[j]
+	})
+	fmt.Fprintf(&sb, "\nClusters made by each rule:\n")
+	for _, name := range names {
+		s.writeCount(&sb, name, s.Rules[name])
+	}
+
+	reasons := make([]InvalidReason, 0, len(s.Reasons))
+	for reason := range s.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		ci, cj := s.Reasons[reasons[i]].Count, s.Reasons[reasons[j]].Count
+		if ci != cj {
+			return ci > cj
+		}
+		return reasons[i] < reasons[j]
+	})
+	fmt.Fprintf(&sb, "\nInvalid clusters by reason:\n")
+	for _, reason := range reasons {
+		s.writeCount(&sb, reason.String(), s.Reasons[reason])
+	}
+
+	unused := s.Unused()
+	fmt.Fprintf(&sb, "\n%d rules never made a cluster:\n", len(unused))
+	for _, name := range unused {
+		fmt.Fprintf(&sb, "  %s\n", name)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (s *RuleStats) writeCount(sb *strings.Builder, name string, rc *RuleCount) {
+	percent := 0.0
+	if s.Clusters > 0 {
+		percent = 100 * float64(rc.Count) / float64(s.Clusters)
+	}
+	fmt.Fprintf(sb, "  %s: %d (%.2f%%)\n", name, rc.Count, percent)
+	for _, sample := range rc.Samples {
+		if sample.Ref != "" {
+			fmt.Fprintf(sb, "    %s in %s\n", sample.Context, sample.Ref)
+		} else {
+			fmt.Fprintf(sb, "    %s\n", sample.Context)
+		}
+	}
+}
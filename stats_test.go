@@ -0,0 +1,55 @@
+package paasaathai
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestRuleStats(c *C) {
+	gcp := NewGStackClusterParser()
+	stats := NewRuleStats(gcp)
+	stats.MaxSamples = 2
+	for i, text := range []string{"สวัสดี", "กา ขา คา", "ุก"} {
+		clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks(text))
+		stats.Add(clusters, []string{"a", "b", "c"}[i])
+	}
+
+	c.Check(stats.Clusters, Equals, 8)
+	c.Check(stats.Rules["consonant"].Count, Equals, 2)
+	c.Check(stats.Rules["sara_a_aa"], DeepEquals, &RuleCount{
+		Count: 3,
+		Samples: []RuleSample{
+			{Ref: "b", Text: "กา", Context: "[กา] ขา "},
+			{Ref: "b", Text: "ขา", Context: "กา [ขา] คา"},
+		},
+	})
+	c.Check(stats.Reasons, DeepEquals, map[InvalidReason]*RuleCount{
+		ReasonSoloDiacritic: {
+			Count:   1,
+			Samples: []RuleSample{{Ref: "c", Text: "ุ", Context: "[ุ]ก"}},
+		},
+	})
+
+	unused := stats.Unused()
+	c.Check(len(unused), Equals, len(gcp.RuleNames())-5)
+	c.Check(unused[0], Equals, "special_o_ang")
+
+	var sb strings.Builder
+	c.Assert(stats.WriteText(&sb), IsNil)
+	c.Check(sb.String(), Matches, `8 Thai clusters
+
+Clusters made by each rule:
+  sara_a_aa: 3 \(37.50%\)
+    \[กา\] ขา  in b
+    กา \[ขา\] คา in b
+  consonant: 2 \(25.00%\)
+(?s:.*)
+Invalid clusters by reason:
+  SoloDiacritic: 1 \(12.50%\)
+    \[ุ\]ก in c
+
+\d+ rules never made a cluster:
+  special_o_ang
+(?s:.*)`)
+}
@@ -0,0 +1,547 @@
+package paasaathai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A phonological syllable, made from one or more GStackClusters.
+// Clusters that are not part of a syllable (non-Thai text, punctuation,
+// digits, and invalid Thai) are put in a Syllable of their own, whose
+// Vowel is UndefinedVowel.
+type Syllable struct {
+	// The UTF-8 string in this syllable
+	Text string
+
+	// Is it comprised completely of Thai code points?
+	IsThai bool
+
+	// Did it contain a valid sequence of Thai code points?
+	IsValidThai bool
+
+	// The clusters that spell this syllable
+	Clusters []GStackCluster
+
+	// Not always set, but if so, this is the vowel that goes in front.
+	FrontVowel rune
+
+	// One or two stacks. With two, the first is either a leading
+	// ho hip or o ang, or the first consonant of an initial cluster,
+	// like กร or ปล.
+	InitialConsonants []GraphemeStack
+
+	Vowel Vowel
+
+	// The pronounced final consonant. Main is 0 if there is none.
+	FinalConsonant GraphemeStack
+
+	// Consonants that are written but not pronounced, like those
+	// under a thanthakhat.
+	SilentConsonants []GraphemeStack
+
+	// 0 if there is no tone mark
+	ToneMark rune
+
+	// The class that decides the tone. For a leading ho hip or o ang,
+	// this is the class of the ho hip or o ang.
+	Class ConsonantClass
+
+	Tone Tone
+}
+
+func (s *Syllable) Repr() string {
+	if !s.IsThai {
+		return fmt.Sprintf("<Syllable Not-Thai: %s>", s.Text)
+	}
+	if !s.IsValidThai {
+		return fmt.Sprintf("<Syllable Invalid-Thai: %s>", s.Text)
+	}
+	if s.Vowel == UndefinedVowel {
+		return fmt.Sprintf("<Syllable Thai %s>", s.Text)
+	}
+	initials := make([]string, len(s.InitialConsonants))
+	for i, gs := range s.InitialConsonants {
+		initials[i] = string(gs.Main)
+	}
+	result := fmt.Sprintf("<Syllable Thai %s I:%s V:%s", s.Text,
+		strings.Join(initials, ""), s.Vowel)
+	if s.FinalConsonant.Main != 0 {
+		result += fmt.Sprintf(" F:%s", string(s.FinalConsonant.Main))
+	}
+	return result + fmt.Sprintf(" %s:%s>", s.Class, s.Tone)
+}
+
+// Does the syllable end in a live sound? This is true for a sonorant
+// final consonant, or for no final consonant after a long vowel
+// (or -ำ, ใ-, ไ-, เ-า).
+func (s *Syllable) IsLive() bool {
+	if s.FinalConsonant.Main != 0 {
+		return LiveFinalConsonantRunes.Has(s.FinalConsonant.Main)
+	}
+	return s.Vowel.EndsLive()
+}
+
+// Groups GStackClusters into Syllables.
+// Thai is written without marking where syllables end, and some
+// words can be read more than one way; ขนม is ขะ-หนม, but these rules,
+// without a dictionary, read it as ขน-ม. The rules are:
+//
+//   - A cluster with a vowel starts a new syllable, unless the
+//     syllable so far is a single bare consonant that leads it
+//     (a leading ho hip or o ang) or forms an initial cluster with it.
+//   - A bare consonant becomes the final consonant of the syllable
+//     so far, if it can be one there. Otherwise, it starts a new
+//     syllable with an implicit vowel.
+//   - A consonant under a thanthakhat is silent, and is kept with
+//     the syllable so far, as is a bare consonant right before it.
+type SyllableParser struct{}
+
+func (s *SyllableParser) ParseGStackClusters(input []GStackCluster) []Syllable {
+	syllables := make([]Syllable, 0, len(input))
+	var cur []GStackCluster
+
+	flush := func() {
+		if len(cur) > 0 {
+			syllables = append(syllables, newSyllable(cur))
+			cur = nil
+		}
+	}
+
+	for i, c := range input {
+		// Not part of any syllable
+		if !c.IsValidThai || c.SingleMidSign.Main != 0 ||
+			!RuneIsConsonant(c.FirstConsonant.Main) {
+			flush()
+			cur = []GStackCluster{c}
+			flush()
+			continue
+		}
+
+		if !clusterIsBareConsonant(c) {
+			if len(cur) == 1 && clusterIsBareConsonant(cur[0]) &&
+				clustersLead(cur[0], c) {
+				cur = append(cur, c)
+				continue
+			}
+			flush()
+			cur = []GStackCluster{c}
+			continue
+		}
+
+		// A bare consonant
+		if len(cur) == 0 {
+			cur = []GStackCluster{c}
+			continue
+		}
+		if c.FirstConsonant.UpperDiacritic == THAI_CHARACTER_THANTHAKHAT {
+			cur = append(cur, c)
+			continue
+		}
+		var next *GStackCluster
+		if i+1 < len(input) {
+			next = &input[i+1]
+		}
+		// The consonant before a thanthakhat is often silent
+		// too, as in จันทร์
+		if next != nil && clusterIsBareConsonant(*next) &&
+			next.FirstConsonant.UpperDiacritic == THAI_CHARACTER_THANTHAKHAT {
+			cur = append(cur, c)
+			continue
+		}
+		if syllableTakesFinal(cur, c, next) {
+			cur = append(cur, c)
+			continue
+		}
+		flush()
+		cur = []GStackCluster{c}
+	}
+	flush()
+
+	return syllables
+}
+
+// Is the cluster a single consonant, without any vowel?
+// It may have a tone mark or thanthakhat.
+func clusterIsBareConsonant(c GStackCluster) bool {
+	fc := c.FirstConsonant
+	return c.FrontVowel.Main == 0 && len(c.Tail) == 0 &&
+		RuneIsConsonant(fc.Main) &&
+		(fc.DiacriticVowel == 0 || fc.DiacriticVowel == THAI_CHARACTER_PHINTHU) &&
+		fc.UpperDiacritic != THAI_CHARACTER_MAITAIKHU
+}
+
+// Does the bare consonant "lead" into the next cluster, as the
+// first of two initial consonants?
+func clustersLead(bare GStackCluster, next GStackCluster) bool {
+	if next.FrontVowel.Main != 0 {
+		return false
+	}
+	syllable, ok := makeSyllable([]GStackCluster{bare, next})
+	return ok && len(syllable.InitialConsonants) == 2
+}
+
+// Consonants that are never pronounced in the final position
+var ConsonantsNotAllowedAsFinal = NewSetFromSlice([]rune{
+	THAI_CHARACTER_CHO_CHING,
+	THAI_CHARACTER_PHO_PHUNG,
+	THAI_CHARACTER_FO_FA,
+	THAI_CHARACTER_HO_HIP,
+	THAI_CHARACTER_O_ANG,
+	THAI_CHARACTER_HO_NOKHUK,
+	THAI_CHARACTER_RU,
+	THAI_CHARACTER_LU,
+})
+
+// Can the syllable so far take the bare consonant as its final?
+func syllableTakesFinal(cur []GStackCluster, c GStackCluster, next *GStackCluster) bool {
+	fc := c.FirstConsonant
+	if RuneIsToneMark(fc.UpperDiacritic) || ConsonantsNotAllowedAsFinal.Has(fc.Main) {
+		return false
+	}
+
+	// If the consonant leads into the next cluster, it starts
+	// the next syllable instead
+	if next != nil && !clusterIsBareConsonant(*next) && next.IsValidThai &&
+		clustersLead(c, *next) {
+		return false
+	}
+
+	sofar, ok := makeSyllable(cur)
+	if !ok {
+		return false
+	}
+	lastCluster := cur[len(cur)-1]
+	if len(lastCluster.Tail) > 0 &&
+		lastCluster.Tail[len(lastCluster.Tail)-1].Main == THAI_CHARACTER_SARA_A {
+		return false
+	}
+	switch sofar.Vowel {
+	case VowelAM, VowelAO, VowelRUE, VowelRUEE, VowelLUE, VowelLUEE:
+		return false
+	case VowelAI:
+		// ไทย
+		return fc.Main == THAI_CHARACTER_YO_YAK && sofar.FinalConsonant.Main == 0
+	}
+
+	combined := make([]GStackCluster, len(cur), len(cur)+1)
+	copy(combined, cur)
+	combined = append(combined, c)
+	syllable, ok := makeSyllable(combined)
+	return ok && syllable.FinalConsonant == fc
+}
+
+// Create a Syllable from the clusters. If they can't be analyzed as
+// a syllable, the Syllable only has the cluster information.
+func newSyllable(clusters []GStackCluster) Syllable {
+	syllable, ok := makeSyllable(clusters)
+	if ok {
+		return syllable
+	}
+
+	syllable = Syllable{
+		IsThai:      true,
+		IsValidThai: true,
+		Clusters:    clusters,
+	}
+	for _, c := range clusters {
+		syllable.Text += c.Text
+		if !c.IsThai {
+			syllable.IsThai = false
+		}
+		if !c.IsValidThai {
+			syllable.IsValidThai = false
+		}
+	}
+	return syllable
+}
+
+// Analyze the clusters as a single syllable.
+func makeSyllable(clusters []GStackCluster) (Syllable, bool) {
+	syllable := Syllable{
+		IsThai:      true,
+		IsValidThai: true,
+		Clusters:    clusters,
+	}
+	for _, c := range clusters {
+		syllable.Text += c.Text
+	}
+	if !analyzeSyllable(clusterStacks(clusters), &syllable) {
+		return syllable, false
+	}
+	syllable.Tone = CalculateTone(syllable.Class, syllable.ToneMark,
+		syllable.IsLive(), syllable.Vowel.IsShort())
+	return syllable, true
+}
+
+// Consonants that combine with a following consonant into a single
+// initial sound, as in กร, ปล, or คว. These are the phonological
+// clusters; the ConsonantsAllowedBeforeGliding* sets are the
+// orthographic ones used to build GStackClusters.
+var initialClusters = map[rune]Set[rune]{
+	THAI_CHARACTER_KO_KAI:     NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_LO_LING, THAI_CHARACTER_WO_WAEN}),
+	THAI_CHARACTER_KHO_KHAI:   NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_LO_LING, THAI_CHARACTER_WO_WAEN}),
+	THAI_CHARACTER_KHO_KHWAI:  NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_LO_LING, THAI_CHARACTER_WO_WAEN}),
+	THAI_CHARACTER_TO_TAO:     NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA}),
+	THAI_CHARACTER_THO_THAHAN: NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA}),
+	THAI_CHARACTER_PO_PLA:     NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_LO_LING}),
+	THAI_CHARACTER_PHO_PHUNG:  NewSetFromSlice([]rune{THAI_CHARACTER_LO_LING}),
+	THAI_CHARACTER_PHO_PHAN:   NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA, THAI_CHARACTER_LO_LING}),
+	// In these, the ro rua is silent, as in สร้าง
+	THAI_CHARACTER_CHO_CHAN: NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA}),
+	THAI_CHARACTER_SO_SO:    NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA}),
+	THAI_CHARACTER_SO_SALA:  NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA}),
+	THAI_CHARACTER_SO_SUA:   NewSetFromSlice([]rune{THAI_CHARACTER_RO_RUA}),
+}
+
+// Flatten the parts of the clusters back into a sequence of
+// GraphemeStacks. Returns nil if any cluster is not valid Thai.
+func clusterStacks(clusters []GStackCluster) []GraphemeStack {
+	stacks := make([]GraphemeStack, 0, len(clusters)*2)
+	for _, c := range clusters {
+		if !c.IsValidThai || c.SingleMidSign.Main != 0 {
+			return nil
+		}
+		if c.FrontVowel.Main != 0 {
+			stacks = append(stacks, c.FrontVowel)
+		}
+		if c.FirstConsonant.Main != 0 {
+			stacks = append(stacks, c.FirstConsonant)
+		}
+		stacks = append(stacks, c.Tail...)
+	}
+	return stacks
+}
+
+// Break the GraphemeStacks of a single syllable into its parts.
+func analyzeSyllable(stacks []GraphemeStack, p *Syllable) bool {
+	var front rune
+	i := 0
+
+	if i < len(stacks) && RuneIsFrontPositionVowel(stacks[i].Main) {
+		front = stacks[i].Main
+		i++
+	}
+	if i >= len(stacks) || !RuneIsConsonant(stacks[i].Main) {
+		return false
+	}
+	p.FrontVowel = front
+	p.InitialConsonants = append(p.InitialConsonants, stacks[i])
+	i++
+	if joinsInitial(front, stacks, i) {
+		p.InitialConsonants = append(p.InitialConsonants, stacks[i])
+		i++
+	}
+
+	// A leading ho hip makes the low class consonant after it high class,
+	// and a leading o ang makes yo yak mid class. In a cluster, the first
+	// consonant decides. So it is always the class of the first initial.
+	p.Class = RuneConsonantClass(p.InitialConsonants[0].Main)
+
+	p.Vowel, i = readVowel(front, p.InitialConsonants, stacks, i)
+
+	for ; i < len(stacks); i++ {
+		st := stacks[i]
+		if !RuneIsConsonant(st.Main) {
+			continue
+		}
+		if p.FinalConsonant.Main != 0 ||
+			st.UpperDiacritic == THAI_CHARACTER_THANTHAKHAT ||
+			(p.Vowel == VowelAI && st.Main == THAI_CHARACTER_YO_YAK) {
+			p.SilentConsonants = append(p.SilentConsonants, st)
+			continue
+		}
+		p.FinalConsonant = st
+	}
+
+	for _, st := range stacks {
+		if RuneIsToneMark(st.UpperDiacritic) {
+			p.ToneMark = st.UpperDiacritic
+			break
+		}
+	}
+	return true
+}
+
+// Does stacks[i] join stacks[i-1] as part of the initial consonant sound?
+func joinsInitial(front rune, stacks []GraphemeStack, i int) bool {
+	if i >= len(stacks) {
+		return false
+	}
+	first := stacks[i-1]
+	second := stacks[i]
+	if first.DiacriticVowel != 0 || first.UpperDiacritic != 0 ||
+		!RuneIsConsonant(second.Main) ||
+		second.UpperDiacritic == THAI_CHARACTER_THANTHAKHAT {
+		return false
+	}
+
+	a, b := first.Main, second.Main
+	if (a == THAI_CHARACTER_HO_HIP && LowConsonantsAllowedAfterHoHip.Has(b)) ||
+		(a == THAI_CHARACTER_O_ANG && b == THAI_CHARACTER_YO_YAK) {
+		return true
+	}
+
+	glides, has := initialClusters[a]
+	if !has || !glides.Has(b) {
+		return false
+	}
+
+	// An explicit vowel follows the cluster,
+	if front != 0 || second.DiacriticVowel != 0 {
+		return true
+	}
+	if i+1 < len(stacks) {
+		next := stacks[i+1].Main
+		if RuneIsMidPositionVowel(next) || next == THAI_CHARACTER_O_ANG {
+			return true
+		}
+	}
+	// or the implicit vowel sits between the cluster and a single
+	// final consonant. A wo waen without an explicit vowel is
+	// the vowel -ว- instead, as in ขวด.
+	return b != THAI_CHARACTER_WO_WAEN && i+2 == len(stacks) &&
+		RuneIsConsonant(stacks[i+1].Main)
+}
+
+// Identify the vowel, starting after the initial consonants at stacks[i].
+// Returns the vowel and the index of the first stack after the vowel.
+func readVowel(front rune, initials []GraphemeStack, stacks []GraphemeStack, i int) (Vowel, int) {
+	last := initials[len(initials)-1]
+	shortened := last.UpperDiacritic == THAI_CHARACTER_MAITAIKHU
+	at := func(n int) rune {
+		if i+n < len(stacks) {
+			return stacks[i+n].Main
+		}
+		return 0
+	}
+
+	switch front {
+	case 0:
+		switch last.DiacriticVowel {
+		case THAI_CHARACTER_MAI_HAN_AKAT:
+			if at(0) == THAI_CHARACTER_WO_WAEN {
+				if at(1) == THAI_CHARACTER_SARA_A {
+					return VowelUA, i + 2
+				}
+				return VowelUAA, i + 1
+			}
+			return VowelA, i
+		case THAI_CHARACTER_SARA_I:
+			return VowelI, i
+		case THAI_CHARACTER_SARA_II:
+			return VowelII, i
+		case THAI_CHARACTER_SARA_UE:
+			return VowelUE, i
+		case THAI_CHARACTER_SARA_UEE:
+			if at(0) == THAI_CHARACTER_O_ANG {
+				return VowelUEE, i + 1
+			}
+			return VowelUEE, i
+		case THAI_CHARACTER_SARA_U:
+			return VowelU, i
+		case THAI_CHARACTER_SARA_UU:
+			return VowelUU, i
+		}
+
+		first := initials[0].Main
+		switch {
+		case at(0) == THAI_CHARACTER_SARA_A:
+			return VowelA, i + 1
+		case at(0) == THAI_CHARACTER_SARA_AA:
+			return VowelAA, i + 1
+		case at(0) == THAI_CHARACTER_SARA_AM:
+			return VowelAM, i + 1
+		case at(0) == THAI_CHARACTER_O_ANG:
+			if shortened {
+				return VowelAW, i + 1
+			}
+			return VowelAAW, i + 1
+		case at(0) == THAI_CHARACTER_WO_WAEN && RuneIsConsonant(at(1)):
+			return VowelUAA, i + 1
+		case at(0) == THAI_CHARACTER_RO_RUA && at(1) == THAI_CHARACTER_RO_RUA:
+			// ro han: รร is -an, or -a- if another final follows
+			if i+2 == len(stacks) {
+				return VowelA, i + 1
+			}
+			return VowelA, i + 2
+		case first == THAI_CHARACTER_RU:
+			if at(0) == THAI_CHARACTER_LAKKHANGYAO {
+				return VowelRUEE, i + 1
+			}
+			return VowelRUE, i
+		case first == THAI_CHARACTER_LU:
+			if at(0) == THAI_CHARACTER_LAKKHANGYAO {
+				return VowelLUEE, i + 1
+			}
+			return VowelLUE, i
+		case shortened:
+			return VowelAW, i
+		case RuneIsConsonant(at(0)):
+			return VowelO, i
+		default:
+			return VowelA, i
+		}
+
+	case THAI_CHARACTER_SARA_E:
+		switch last.DiacriticVowel {
+		case THAI_CHARACTER_SARA_II:
+			if at(0) == THAI_CHARACTER_YO_YAK {
+				if at(1) == THAI_CHARACTER_SARA_A {
+					return VowelIA, i + 2
+				}
+				return VowelIAA, i + 1
+			}
+			return VowelIAA, i
+		case THAI_CHARACTER_SARA_UEE:
+			if at(0) == THAI_CHARACTER_O_ANG {
+				if at(1) == THAI_CHARACTER_SARA_A {
+					return VowelUEA, i + 2
+				}
+				return VowelUEAA, i + 1
+			}
+			return VowelUEAA, i
+		case THAI_CHARACTER_SARA_I:
+			return VowelOEE, i
+		}
+		switch {
+		case at(0) == THAI_CHARACTER_SARA_AA:
+			if at(1) == THAI_CHARACTER_SARA_A {
+				return VowelAW, i + 2
+			}
+			return VowelAO, i + 1
+		case at(0) == THAI_CHARACTER_O_ANG:
+			if at(1) == THAI_CHARACTER_SARA_A {
+				return VowelOE, i + 2
+			}
+			return VowelOEE, i + 1
+		case at(0) == THAI_CHARACTER_SARA_A:
+			return VowelE, i + 1
+		case at(0) == THAI_CHARACTER_YO_YAK && i+1 == len(stacks):
+			// เ-อ loses its o ang before a final yo yak, as in เลย
+			return VowelOEE, i
+		case shortened:
+			return VowelE, i
+		default:
+			return VowelEE, i
+		}
+
+	case THAI_CHARACTER_SARA_AE:
+		if at(0) == THAI_CHARACTER_SARA_A {
+			return VowelAE, i + 1
+		}
+		if shortened {
+			return VowelAE, i
+		}
+		return VowelAEE, i
+
+	case THAI_CHARACTER_SARA_O:
+		if at(0) == THAI_CHARACTER_SARA_A {
+			return VowelO, i + 1
+		}
+		return VowelOO, i
+
+	case THAI_CHARACTER_SARA_AI_MAIMUAN, THAI_CHARACTER_SARA_AI_MAIMALAI:
+		return VowelAI, i
+	}
+	return UndefinedVowel, i
+}
@@ -0,0 +1,101 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func parseSyllables(input string) []Syllable {
+	gcp := NewGStackClusterParser()
+	var sp SyllableParser
+	return sp.ParseGStackClusters(gcp.ParseGraphemeStacks(ParseGraphemeStacks(input)))
+}
+
+func syllableTexts(syllables []Syllable) []string {
+	texts := make([]string, len(syllables))
+	for i, s := range syllables {
+		texts[i] = s.Text
+	}
+	return texts
+}
+
+func (s *MySuite) TestSyllableSawatdee(c *C) {
+	syls := parseSyllables("สวัสดี")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"ส", "วัส", "ดี"})
+
+	// The implicit vowel
+	c.Check(syls[0].Vowel, Equals, Vowel(VowelA))
+	c.Check(syls[0].Tone, Equals, Tone(LowTone))
+
+	c.Check(syls[1].InitialConsonants[0].Main, Equals, THAI_CHARACTER_WO_WAEN)
+	c.Check(syls[1].Vowel, Equals, Vowel(VowelA))
+	c.Check(syls[1].FinalConsonant.Main, Equals, THAI_CHARACTER_SO_SUA)
+	c.Check(syls[1].Tone, Equals, Tone(HighTone))
+
+	c.Check(syls[2].Vowel, Equals, Vowel(VowelII))
+	c.Check(syls[2].Tone, Equals, Tone(MidTone))
+}
+
+func (s *MySuite) TestSyllableFinalConsonants(c *C) {
+	syls := parseSyllables("ขอบคุณมากมาย")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"ขอบ", "คุณ", "มาก", "มาย"})
+
+	c.Check(syls[0].Vowel, Equals, Vowel(VowelAAW))
+	c.Check(syls[0].FinalConsonant.Main, Equals, THAI_CHARACTER_BO_BAIMAI)
+	c.Check(syls[1].FinalConsonant.Main, Equals, THAI_CHARACTER_NO_NEN)
+	c.Check(syls[2].Tone, Equals, Tone(FallingTone))
+	c.Check(syls[3].Tone, Equals, Tone(MidTone))
+}
+
+func (s *MySuite) TestSyllableLeadingConsonants(c *C) {
+	syls := parseSyllables("บ้านหลัง")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"บ้าน", "หลัง"})
+
+	c.Check(syls[0].ToneMark, Equals, THAI_CHARACTER_MAI_THO)
+	c.Check(syls[0].Tone, Equals, Tone(FallingTone))
+
+	c.Assert(len(syls[1].InitialConsonants), Equals, 2)
+	c.Check(syls[1].InitialConsonants[0].Main, Equals, THAI_CHARACTER_HO_HIP)
+	c.Check(syls[1].InitialConsonants[1].Main, Equals, THAI_CHARACTER_LO_LING)
+	c.Check(syls[1].Class, Equals, ConsonantClass(HighClass))
+	c.Check(syls[1].Tone, Equals, Tone(RisingTone))
+
+	syls = parseSyllables("อยู่")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"อยู่"})
+	c.Check(syls[0].Class, Equals, ConsonantClass(MidClass))
+	c.Check(syls[0].Tone, Equals, Tone(LowTone))
+}
+
+func (s *MySuite) TestSyllableInitialCluster(c *C) {
+	syls := parseSyllables("ประเทศไทย")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"ประ", "เทศ", "ไทย"})
+
+	c.Assert(len(syls[0].InitialConsonants), Equals, 2)
+	c.Check(syls[0].InitialConsonants[1].Main, Equals, THAI_CHARACTER_RO_RUA)
+	c.Check(syls[1].FrontVowel, Equals, THAI_CHARACTER_SARA_E)
+	c.Check(syls[1].Vowel, Equals, Vowel(VowelEE))
+	c.Check(syls[1].FinalConsonant.Main, Equals, THAI_CHARACTER_SO_SALA)
+	c.Check(syls[2].Vowel, Equals, Vowel(VowelAI))
+	c.Check(syls[2].FinalConsonant.Main, Equals, rune(0))
+	c.Assert(len(syls[2].SilentConsonants), Equals, 1)
+	c.Check(syls[2].SilentConsonants[0].Main, Equals, THAI_CHARACTER_YO_YAK)
+
+	syls = parseSyllables("กรม")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"กรม"})
+	c.Check(syls[0].Vowel, Equals, Vowel(VowelO))
+}
+
+func (s *MySuite) TestSyllableSilent(c *C) {
+	syls := parseSyllables("จันทร์")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"จันทร์"})
+	c.Check(syls[0].FinalConsonant.Main, Equals, THAI_CHARACTER_NO_NU)
+	c.Check(len(syls[0].SilentConsonants), Equals, 2)
+}
+
+func (s *MySuite) TestSyllableNotThai(c *C) {
+	syls := parseSyllables("a กิน")
+	c.Assert(syllableTexts(syls), DeepEquals, []string{"a", " ", "กิน"})
+	c.Check(syls[0].IsThai, Equals, false)
+	c.Check(syls[0].Vowel, Equals, Vowel(UndefinedVowel))
+	c.Check(syls[2].IsValidThai, Equals, true)
+	c.Check(syls[2].Vowel, Equals, Vowel(VowelI))
+}
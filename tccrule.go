@@ -0,0 +1,194 @@
+package paasaathai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gilramir/objregexp"
+)
+
+// Fills in a cluster from a successful match of a rule's pattern at
+// position i of the input, and returns the number of GraphemeStacks in
+// the cluster, which is usually m.Length(). Returning 0 rejects the
+// match, and the next rule is tried.
+type TccExtractFunc func(m objregexp.Match, input []GraphemeStack, i int, c *GStackCluster) int
+
+// Make a rule which can be added to a GStackClusterParser. The pattern
+// is an objregexp regex, which can use the classes that the parser
+// defines, like [:consonant:] and [:sara aa:].
+func NewTccRule(name string, pattern string, extract TccExtractFunc) *TccRule {
+	return &TccRule{
+		name:    name,
+		rs:      pattern,
+		extract: extract,
+	}
+}
+
+func (s *TccRule) Name() string {
+	return s.name
+}
+
+func (s *TccRule) Pattern() string {
+	return s.rs
+}
+
+// This rule must be tried before the named rules. Returns the rule,
+// so that calls can be chained.
+func (s *TccRule) Before(names ...string) *TccRule {
+	s.before = append(s.before, names...)
+	return s
+}
+
+// This rule must be tried after the named rules. Returns the rule,
+// so that calls can be chained.
+func (s *TccRule) After(names ...string) *TccRule {
+	s.after = append(s.after, names...)
+	return s
+}
+
+func (s *TccRule) match(input []GraphemeStack, i int, length *int, c *GStackCluster) bool {
+	if s.ck != nil {
+		return s.ck(s, input, i, length, c)
+	}
+	m := s.regex.MatchAt(input, i)
+	if !m.Success {
+		return false
+	}
+	*length = s.extract(m, input, i, c)
+	return *length > 0
+}
+
+// Make a cluster from stacks, setting its Text, IsThai, IsValidThai,
+// and offsets. This is for TccExtractFuncs.
+func NewGStackCluster(input []GraphemeStack) GStackCluster {
+	return makeCluster(input)
+}
+
+// Add a rule to try along with the built-in rules. Unless its
+// constraints say otherwise, it is tried just before the catch-all
+// "consonant" rule, which matches any consonant, and after the rules
+// added before it. The error rules come after that. This must be
+// called before Initialize; it panics after that.
+func (s *GStackClusterParser) AddRule(rule *TccRule) {
+	if s.initialized {
+		panic(fmt.Sprintf("AddRule %s: %s", rule.name, ParserAlreadyInitializedError))
+	}
+	s.addedRules = append(s.addedRules, rule)
+}
+
+// The names of the rules, in the order that they are tried
+func (s *GStackClusterParser) RuleNames() []string {
+	names := make([]string, len(s.rules))
+	for i := range s.rules {
+		names[i] = s.rules[i].name
+	}
+	return names
+}
+
+// Returned by GStackClusterParser.Initialize when the rules can't
+// be put in order
+type TccRuleOrderError struct {
+	Problems []string
+}
+
+func (s *TccRuleOrderError) Error() string {
+	return "TCC rules can't be ordered: " + strings.Join(s.Problems, "; ")
+}
+
+// Sort the rules so that every before and after constraint is met,
+// keeping the given order as much as possible.
+func orderTccRules(rules []*TccRule) ([]*TccRule, error) {
+	var problems []string
+
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		if rule.name == "" {
+			problems = append(problems, fmt.Sprintf("rule #%d has no name", i))
+			continue
+		}
+		if rule.ck == nil && rule.extract == nil {
+			problems = append(problems,
+				fmt.Sprintf("rule %s has no extraction function", rule.name))
+		}
+		if _, has := index[rule.name]; has {
+			problems = append(problems,
+				fmt.Sprintf("rule %s is defined more than once", rule.name))
+			continue
+		}
+		index[rule.name] = i
+	}
+
+	// successors[i] are the rules that must come after rule i
+	successors := make([][]int, len(rules))
+	predecessors := make([]int, len(rules))
+	addEdge := func(from, to int) {
+		successors[from] = append(successors[from], to)
+		predecessors[to]++
+	}
+	for i, rule := range rules {
+		for _, name := range rule.before {
+			j, has := index[name]
+			switch {
+			case !has:
+				problems = append(problems,
+					fmt.Sprintf("rule %s must come before unknown rule %s", rule.name, name))
+			case j == i:
+				problems = append(problems,
+					fmt.Sprintf("rule %s must come before itself", rule.name))
+			default:
+				addEdge(i, j)
+			}
+		}
+		for _, name := range rule.after {
+			j, has := index[name]
+			switch {
+			case !has:
+				problems = append(problems,
+					fmt.Sprintf("rule %s must come after unknown rule %s", rule.name, name))
+			case j == i:
+				problems = append(problems,
+					fmt.Sprintf("rule %s must come after itself", rule.name))
+			default:
+				addEdge(j, i)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &TccRuleOrderError{Problems: problems}
+	}
+
+	// Kahn's algorithm, always taking the earliest rule that is ready
+	ordered := make([]*TccRule, 0, len(rules))
+	placed := make([]bool, len(rules))
+	for len(ordered) < len(rules) {
+		next := -1
+		for i := range rules {
+			if !placed[i] && predecessors[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			break
+		}
+		placed[next] = true
+		ordered = append(ordered, rules[next])
+		for _, j := range successors[next] {
+			predecessors[j]--
+		}
+	}
+
+	if len(ordered) < len(rules) {
+		var names []string
+		for i, rule := range rules {
+			if !placed[i] {
+				names = append(names, rule.name)
+			}
+		}
+		return nil, &TccRuleOrderError{Problems: []string{
+			"the constraints of these rules form a cycle: " +
+				strings.Join(names, ", "),
+		}}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,106 @@
+package paasaathai
+
+import (
+	"github.com/gilramir/objregexp"
+	. "gopkg.in/check.v1"
+)
+
+func extractWhole(m objregexp.Match, input []GraphemeStack, i int, c *GStackCluster) int {
+	*c = NewGStackCluster(input[i : i+m.Length()])
+	c.FirstConsonant = input[i]
+	c.Tail = append(c.Tail, input[i+1:i+m.Length()]...)
+	return m.Length()
+}
+
+func (s *MySuite) TestTccRuleBuiltinOrder(c *C) {
+	names := NewGStackClusterParser().RuleNames()
+	c.Assert(len(names), Equals, len(tccRules))
+	for i, rule := range tccRules {
+		c.Check(names[i], Equals, rule.name)
+	}
+}
+
+func (s *MySuite) TestAddTccRule(c *C) {
+	var gcp GStackClusterParser
+	gcp.AddRule(NewTccRule("kho_kho", "[:bare kho khwai:] [:bare kho khwai:]",
+		extractWhole).Before("consonant"))
+	gcp.AddRule(NewTccRule("last", "[:bare ngo ngu:]", extractWhole))
+	err := gcp.Initialize()
+	c.Assert(err, IsNil)
+
+	// Both go before the catch-all consonant rule
+	names := gcp.RuleNames()
+	for i, name := range names {
+		if name == "consonant" {
+			c.Check(names[i-2:i], DeepEquals, []string{"kho_kho", "last"})
+		}
+	}
+
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("คคกง"))
+	c.Assert(len(clusters), Equals, 3)
+	c.Check(clusters[0].Text, Equals, "คค")
+	c.Check(clusters[0].MatchingRule, Equals, "kho_kho")
+	c.Check(clusters[0].IsValidThai, Equals, true)
+	c.Check(clusters[1].MatchingRule, Equals, "consonant")
+	c.Check(clusters[2].MatchingRule, Equals, "last")
+}
+
+// An added rule with no constraints is tried before the catch-all
+// consonant rule, which matches the same consonant
+func (s *MySuite) TestAddTccRuleBeforeConsonant(c *C) {
+	var gcp GStackClusterParser
+	gcp.AddRule(NewTccRule("ko_kai", "[:bare ko kai:]", extractWhole))
+	c.Assert(gcp.Initialize(), IsNil)
+
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("กข"))
+	c.Assert(len(clusters), Equals, 2)
+	c.Check(clusters[0].MatchingRule, Equals, "ko_kai")
+	c.Check(clusters[1].MatchingRule, Equals, "consonant")
+}
+
+// An extraction function can reject a match
+func (s *MySuite) TestAddTccRuleReject(c *C) {
+	var gcp GStackClusterParser
+	gcp.AddRule(NewTccRule("never", "[:consonant:]",
+		func(m objregexp.Match, input []GraphemeStack, i int, c *GStackCluster) int {
+			return 0
+		}).Before("consonant"))
+	c.Assert(gcp.Initialize(), IsNil)
+
+	clusters := gcp.ParseGraphemeStacks(ParseGraphemeStacks("ก"))
+	c.Assert(len(clusters), Equals, 1)
+	c.Check(clusters[0].MatchingRule, Equals, "consonant")
+}
+
+func (s *MySuite) TestAddTccRuleConflicts(c *C) {
+	var gcp GStackClusterParser
+	gcp.AddRule(NewTccRule("a", "[:consonant:]", extractWhole).Before("b"))
+	gcp.AddRule(NewTccRule("b", "[:consonant:]", extractWhole).Before("a"))
+	err := gcp.Initialize()
+	c.Assert(err, FitsTypeOf, &TccRuleOrderError{})
+	c.Check(err, ErrorMatches, ".*form a cycle: a, b")
+
+	gcp = GStackClusterParser{}
+	gcp.AddRule(NewTccRule("consonant", "[:consonant:]", extractWhole))
+	gcp.AddRule(NewTccRule("c", "[:consonant:]", extractWhole).After("nothing"))
+	gcp.AddRule(NewTccRule("d", "[:consonant:]", extractWhole).Before("d"))
+	err = gcp.Initialize()
+	c.Assert(err, FitsTypeOf, &TccRuleOrderError{})
+	c.Check(err.(*TccRuleOrderError).Problems, DeepEquals, []string{
+		"rule consonant is defined more than once",
+		"rule c must come after unknown rule nothing",
+		"rule d must come before itself",
+	})
+
+	gcp = GStackClusterParser{}
+	gcp.AddRule(NewTccRule("bad", "[:no such class:]", extractWhole))
+	err = gcp.Initialize()
+	c.Check(err, ErrorMatches, "Compiling rule bad: .*")
+}
+
+func (s *MySuite) TestAddRuleAfterInitialize(c *C) {
+	gcp := NewGStackClusterParser()
+	c.Check(func() { gcp.AddRule(NewTccRule("x", "[:consonant:]", extractWhole)) },
+		PanicMatches, "AddRule x: The GStackClusterParser is already initialized")
+	c.Check(gcp.RuleNames(), DeepEquals, NewGStackClusterParser().RuleNames())
+}
@@ -28,3 +28,65 @@ func (s Tone) String() string {
 		return "?"
 	}
 }
+
+// Apply the tone rules. The tone of a syllable depends on the class of
+// its initial consonant, its tone mark (or 0 if there is none), whether
+// the syllable is live or dead, and, for dead syllables with a low class
+// initial consonant, whether the vowel is short or long.
+func CalculateTone(class ConsonantClass, toneMark rune, isLive bool, isShortVowel bool) Tone {
+	switch toneMark {
+	case 0:
+		switch class {
+		case HighClass:
+			if isLive {
+				return RisingTone
+			}
+			return LowTone
+		case MidClass:
+			if isLive {
+				return MidTone
+			}
+			return LowTone
+		case LowClass:
+			if isLive {
+				return MidTone
+			}
+			if isShortVowel {
+				return HighTone
+			}
+			return FallingTone
+		}
+	case THAI_CHARACTER_MAI_EK:
+		switch class {
+		case HighClass, MidClass:
+			return LowTone
+		case LowClass:
+			return FallingTone
+		}
+	case THAI_CHARACTER_MAI_THO:
+		switch class {
+		case HighClass, MidClass:
+			return FallingTone
+		case LowClass:
+			return HighTone
+		}
+	case THAI_CHARACTER_MAI_TRI:
+		// Properly only used on mid class consonants
+		return HighTone
+	case THAI_CHARACTER_MAI_CHATTAWA:
+		// Properly only used on mid class consonants
+		return RisingTone
+	}
+	return UndefinedTone
+}
+
+// Compute the tone of one syllable, given the GStackClusters that
+// spell it. UndefinedTone is returned if the clusters cannot be
+// analyzed as a single syllable.
+func ClustersTone(clusters []GStackCluster) Tone {
+	syllable, ok := makeSyllable(clusters)
+	if !ok {
+		return UndefinedTone
+	}
+	return syllable.Tone
+}
@@ -0,0 +1,74 @@
+package paasaathai
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestCalculateTone(c *C) {
+	c.Check(CalculateTone(MidClass, 0, true, false), Equals, Tone(MidTone))
+	c.Check(CalculateTone(MidClass, 0, false, true), Equals, Tone(LowTone))
+	c.Check(CalculateTone(HighClass, 0, true, false), Equals, Tone(RisingTone))
+	c.Check(CalculateTone(HighClass, 0, false, false), Equals, Tone(LowTone))
+	c.Check(CalculateTone(LowClass, 0, true, true), Equals, Tone(MidTone))
+	c.Check(CalculateTone(LowClass, 0, false, true), Equals, Tone(HighTone))
+	c.Check(CalculateTone(LowClass, 0, false, false), Equals, Tone(FallingTone))
+	c.Check(CalculateTone(HighClass, THAI_CHARACTER_MAI_EK, true, false), Equals, Tone(LowTone))
+	c.Check(CalculateTone(LowClass, THAI_CHARACTER_MAI_EK, true, false), Equals, Tone(FallingTone))
+	c.Check(CalculateTone(MidClass, THAI_CHARACTER_MAI_THO, true, false), Equals, Tone(FallingTone))
+	c.Check(CalculateTone(LowClass, THAI_CHARACTER_MAI_THO, true, false), Equals, Tone(HighTone))
+	c.Check(CalculateTone(MidClass, THAI_CHARACTER_MAI_TRI, true, true), Equals, Tone(HighTone))
+	c.Check(CalculateTone(MidClass, THAI_CHARACTER_MAI_CHATTAWA, true, true), Equals, Tone(RisingTone))
+	c.Check(CalculateTone(UndefinedClass, 0, true, true), Equals, Tone(UndefinedTone))
+}
+
+func (s *MySuite) TestClustersTone(c *C) {
+	gcp := NewGStackClusterParser()
+
+	// Each of these is a single syllable
+	expected := []struct {
+		word string
+		tone Tone
+	}{
+		{"กา", MidTone},
+		{"ขา", RisingTone},
+		{"คา", MidTone},
+		{"จะ", LowTone},
+		{"คะ", HighTone},
+		{"มาก", FallingTone},
+		{"ไม่", FallingTone},
+		{"ม้า", HighTone},
+		{"ข้าว", FallingTone},
+		{"โต๊ะ", HighTone},
+		{"เด็ก", LowTone},
+		{"สวย", RisingTone},
+		{"ขวด", LowTone},
+		{"กวาง", MidTone},
+		{"น้ำ", HighTone},
+		{"คน", MidTone},
+		{"ครบ", HighTone},
+		{"เรียน", MidTone},
+		{"เมือง", MidTone},
+		{"ใหม่", LowTone},
+		{"ไหม", RisingTone},
+		// leading ho hip
+		{"หมา", RisingTone},
+		{"หมด", LowTone},
+		// leading o ang
+		{"อยู่", LowTone},
+		{"อยาก", LowTone},
+		// silent letters
+		{"จันทร์", MidTone},
+	}
+
+	for _, e := range expected {
+		gcs := gcp.ParseGraphemeStacks(ParseGraphemeStacks(e.word))
+		c.Check(ClustersTone(gcs), Equals, e.tone, Commentf(e.word))
+	}
+}
+
+func (s *MySuite) TestClustersToneInvalid(c *C) {
+	gcp := NewGStackClusterParser()
+
+	gcs := gcp.ParseGraphemeStacks(ParseGraphemeStacks("าก"))
+	c.Check(ClustersTone(gcs), Equals, Tone(UndefinedTone))
+}
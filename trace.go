@@ -0,0 +1,174 @@
+package paasaathai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gilramir/objregexp"
+)
+
+// What happened when the parser tried a rule at a position
+type TraceEvent struct {
+	// The position in the GraphemeStacks where the rule was tried
+	Position int
+	Rule     string
+
+	// Did the rule's pattern match? If so, the stacks that it matched,
+	// and the stacks that its groups captured; Groups[0] is group 1. A
+	// group that captured nothing has a Start of -1.
+	PatternMatched bool
+	Range          objregexp.Range
+	Groups         []objregexp.Range
+
+	// Did the rule make a cluster? A rule can reject a match of its
+	// pattern, and then the next rule is tried.
+	Matched bool
+
+	// If it matched, the number of stacks in the cluster, and the
+	// cluster
+	Length  int
+	Cluster GStackCluster
+}
+
+// Called by the parser for every rule that it tries, in order. The
+// event is only valid during the call.
+type Tracer func(input []GraphemeStack, e *TraceEvent)
+
+// A copy of the parser which calls the tracer as it parses. It shares
+// the rules of this parser, so it is cheap to make one for each text
+// to trace; the tracer is only called by the copy.
+func (s *GStackClusterParser) WithTracer(tracer Tracer) *GStackClusterParser {
+	traced := *s
+	traced.tracer = tracer
+	return &traced
+}
+
+func (s *GStackClusterParser) trace(rule *TccRule, input []GraphemeStack, i int,
+	matched bool, length int, c *GStackCluster) {
+
+	e := TraceEvent{
+		Position: i,
+		Rule:     rule.name,
+		Matched:  matched,
+	}
+	// The rules don't keep their matches, so match again
+	if m := rule.regex.MatchAt(input, i); m.Success {
+		e.PatternMatched = true
+		e.Range = m.Range
+		n, _ := patternGroups(rule.rs)
+		e.Groups = make([]objregexp.Range, n)
+		for g := 1; g <= n; g++ {
+			e.Groups[g-1] = m.Group(g)
+		}
+	}
+	if matched {
+		e.Length = length
+		e.Cluster = *c
+	}
+	s.tracer(input, &e)
+}
+
+// The number of groups in a pattern, and the numbers of its named
+// groups. Every left parenthesis begins a group, as objregexp numbers
+// them, except in a class like [:consonant: && (:sara uee:)], which
+// ends at its first right bracket.
+func patternGroups(pattern string) (int, map[string]int) {
+	groups := 0
+	names := make(map[string]int)
+	inClass := false
+	for i, r := range pattern {
+		switch {
+		case inClass:
+			inClass = r != ']'
+		case r == '[':
+			inClass = true
+		case r == '(':
+			groups++
+			if rest := pattern[i+1:]; strings.HasPrefix(rest, "?P<") {
+				if end := strings.IndexByte(rest, '>'); end >= 0 {
+					names[rest[len("?P<"):end]] = groups
+				}
+			}
+		}
+	}
+	return groups, names
+}
+
+// Explain, for people, why the parser splits the text into the clusters
+// it does: for each cluster, the rule that made it, what the groups of
+// its pattern captured, and the rules tried before it whose patterns
+// matched, but which did not make a cluster.
+func (s *GStackClusterParser) Explain(text string) string {
+	input := ParseGraphemeStacks(text)
+
+	// The events at each position
+	events := make([][]TraceEvent, len(input))
+	traced := s.WithTracer(func(input []GraphemeStack, e *TraceEvent) {
+		events[e.Position] = append(events[e.Position], *e)
+	})
+	clusters := traced.ParseGraphemeStacks(input)
+
+	patterns := make(map[string]string, len(s.rules))
+	for i := range s.rules {
+		patterns[s.rules[i].name] = s.rules[i].rs
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d stacks, %d clusters\n", text, len(input), len(clusters))
+	i := 0
+	for ci := range clusters {
+		cc := &clusters[ci]
+		fmt.Fprintf(&sb, "\n%s, at stack %d: ", cc.Text, i)
+		switch {
+		case !cc.IsThai:
+			fmt.Fprintf(&sb, "not Thai, so it is a cluster by itself\n")
+		case cc.MatchingRule == "":
+			fmt.Fprintf(&sb, "no rule matched\n")
+		default:
+			fmt.Fprintf(&sb, "rule %s\n", cc.MatchingRule)
+		}
+		if cc.IsThai && !cc.IsValidThai {
+			fmt.Fprintf(&sb, "  invalid: %s\n", cc.InvalidReason.Message())
+		}
+
+		length := 1
+		notMatched := 0
+		for _, e := range events[i] {
+			switch {
+			case e.Matched:
+				length = e.Length
+				fmt.Fprintf(&sb, "  pattern: %s\n", patterns[e.Rule])
+				for g, reg := range e.Groups {
+					if reg.Start >= 0 && !reg.Empty() {
+						fmt.Fprintf(&sb, "  group %d: %s\n", g+1, stacksText(input, reg))
+					}
+				}
+			case e.PatternMatched:
+				fmt.Fprintf(&sb, "  rule %s matched %s first, but did not make a cluster of it\n",
+					e.Rule, stacksText(input, e.Range))
+			default:
+				notMatched++
+			}
+		}
+		if notMatched > 0 {
+			fmt.Fprintf(&sb, "  %d other rules did not match\n", notMatched)
+		}
+		i += length
+	}
+	return sb.String()
+}
+
+// The text of the stacks in the range
+func stacksText(input []GraphemeStack, reg objregexp.Range) string {
+	if reg.Start < 0 || reg.End < reg.Start {
+		return "(nothing)"
+	}
+	var sb strings.Builder
+	for _, gs := range input[reg.Start:reg.End] {
+		sb.WriteString(gs.Text)
+	}
+	if sb.Len() == 0 {
+		return "(nothing)"
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package paasaathai
+
+import (
+	"fmt"
+
+	"github.com/gilramir/objregexp"
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestTracer(c *C) {
+	gcp := NewGStackClusterParser()
+	var events []TraceEvent
+	traced := gcp.WithTracer(func(input []GraphemeStack, e *TraceEvent) {
+		events = append(events, *e)
+	})
+	clusters := traced.ParseGraphemeStacks(ParseGraphemeStacks("กา"))
+	c.Assert(clusters, HasLen, 1)
+
+	// Every rule up to the one that matched was tried
+	names := gcp.RuleNames()
+	c.Assert(events, Not(HasLen), 0)
+	last := events[len(events)-1]
+	c.Check(last.Rule, Equals, "sara_a_aa")
+	c.Check(last.Matched, Equals, true)
+	c.Check(last.Length, Equals, 2)
+	c.Check(last.Cluster.Text, Equals, "กา")
+	c.Check(last.Range, Equals, objregexp.Range{Start: 0, End: 2})
+	c.Check(last.Groups, DeepEquals, []objregexp.Range{{Start: 0, End: 1}, {Start: 1, End: 2}})
+	for i, e := range events[:len(events)-1] {
+		c.Check(e.Rule, Equals, names[i])
+		c.Check(e.Position, Equals, 0)
+		c.Check(e.Matched, Equals, false)
+	}
+
+	// The shared parser does not trace
+	events = nil
+	gcp.ParseGraphemeStacks(ParseGraphemeStacks("กา"))
+	c.Check(events, IsNil)
+}
+
+func (s *MySuite) TestExplain(c *C) {
+	var gcp GStackClusterParser
+	never := func(m objregexp.Match, input []GraphemeStack, i int, cc *GStackCluster) int {
+		return 0
+	}
+	gcp.AddRule(NewTccRule("never", "[:consonant:]", never).Before("consonant"))
+	c.Assert(gcp.Initialize(), IsNil)
+
+	// Every rule before consonant, except never, did not match
+	notMatched := 0
+	for _, name := range gcp.RuleNames() {
+		if name == "consonant" {
+			break
+		}
+		if name != "never" {
+			notMatched++
+		}
+	}
+	c.Check(gcp.Explain("กa"), Equals, fmt.Sprintf(`กa: 2 stacks, 2 clusters
+
+ก, at stack 0: rule consonant
+  rule never matched ก first, but did not make a cluster of it
+  pattern: [:consonant: && !:diacritic vowel:]
+  %d other rules did not match
+
+a, at stack 1: not Thai, so it is a cluster by itself
+`, notMatched))
+
+	explanation := gcp.Explain("วัส")
+	c.Check(explanation, Matches, `(?s).*rule mai_han_akat
+  pattern: \(\[:consonant: && :mai han akat:\]\) \(\[:consonant:\]\)
+  group 1: วั
+  group 2: ส
+.*`)
+}
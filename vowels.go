@@ -0,0 +1,137 @@
+package paasaathai
+
+// The vowel sounds. These are the phonological vowels of a syllable, not
+// the vowel code points. A single Vowel can be written with several code
+// points around the consonant, and some are not written at all.
+type Vowel int
+
+const (
+	UndefinedVowel Vowel = 0
+	VowelA               = 1  // -ะ, -ั-, or implicit
+	VowelAA              = 2  // -า
+	VowelI               = 3  // -ิ
+	VowelII              = 4  // -ี
+	VowelUE              = 5  // -ึ
+	VowelUEE             = 6  // -ือ, -ื-
+	VowelU               = 7  // -ุ
+	VowelUU              = 8  // -ู
+	VowelE               = 9  // เ-ะ, เ-็-
+	VowelEE              = 10 // เ-
+	VowelAE              = 11 // แ-ะ, แ-็-
+	VowelAEE             = 12 // แ-
+	VowelO               = 13 // โ-ะ, or implicit between two consonants
+	VowelOO              = 14 // โ-
+	VowelAW              = 15 // เ-าะ, -็อ-
+	VowelAAW             = 16 // -อ
+	VowelOE              = 17 // เ-อะ
+	VowelOEE             = 18 // เ-อ, เ-ิ-
+	VowelIA              = 19 // เ-ียะ
+	VowelIAA             = 20 // เ-ีย
+	VowelUEA             = 21 // เ-ือะ
+	VowelUEAA            = 22 // เ-ือ
+	VowelUA              = 23 // -ัวะ
+	VowelUAA             = 24 // -ัว, -ว-
+	VowelAM              = 25 // -ำ
+	VowelAI              = 26 // ใ-, ไ-
+	VowelAO              = 27 // เ-า
+	VowelRUE             = 28 // ฤ
+	VowelRUEE            = 29 // ฤๅ
+	VowelLUE             = 30 // ฦ
+	VowelLUEE            = 31 // ฦๅ
+)
+
+func (s Vowel) String() string {
+	switch s {
+	case VowelA:
+		return "a"
+	case VowelAA:
+		return "aa"
+	case VowelI:
+		return "i"
+	case VowelII:
+		return "ii"
+	case VowelUE:
+		return "ue"
+	case VowelUEE:
+		return "uee"
+	case VowelU:
+		return "u"
+	case VowelUU:
+		return "uu"
+	case VowelE:
+		return "e"
+	case VowelEE:
+		return "ee"
+	case VowelAE:
+		return "ae"
+	case VowelAEE:
+		return "aee"
+	case VowelO:
+		return "o"
+	case VowelOO:
+		return "oo"
+	case VowelAW:
+		return "aw"
+	case VowelAAW:
+		return "aaw"
+	case VowelOE:
+		return "oe"
+	case VowelOEE:
+		return "oee"
+	case VowelIA:
+		return "ia"
+	case VowelIAA:
+		return "iaa"
+	case VowelUEA:
+		return "uea"
+	case VowelUEAA:
+		return "ueaa"
+	case VowelUA:
+		return "ua"
+	case VowelUAA:
+		return "uaa"
+	case VowelAM:
+		return "am"
+	case VowelAI:
+		return "ai"
+	case VowelAO:
+		return "ao"
+	case VowelRUE:
+		return "rue"
+	case VowelRUEE:
+		return "ruee"
+	case VowelLUE:
+		return "lue"
+	case VowelLUEE:
+		return "luee"
+	default:
+		return "?"
+	}
+}
+
+// Is this a short vowel? VowelAM, VowelAI and VowelAO are short, even
+// though they end in a live sound; see EndsLive.
+func (s Vowel) IsShort() bool {
+	switch s {
+	case VowelA, VowelI, VowelUE, VowelU, VowelE, VowelAE, VowelO,
+		VowelAW, VowelOE, VowelIA, VowelUEA, VowelUA,
+		VowelAM, VowelAI, VowelAO, VowelRUE, VowelLUE:
+		return true
+	default:
+		return false
+	}
+}
+
+// Does a syllable with this vowel, and no final consonant, end
+// in a live sound? Long vowels do, and so do the short vowels that
+// carry their own final sound (-ำ, ใ-, ไ-, เ-า).
+func (s Vowel) EndsLive() bool {
+	switch s {
+	case UndefinedVowel:
+		return false
+	case VowelAM, VowelAI, VowelAO:
+		return true
+	default:
+		return !s.IsShort()
+	}
+}
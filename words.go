@@ -0,0 +1,204 @@
+package paasaathai
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A list of known words, for segmenting text into words.
+type Dictionary struct {
+	words Set[string]
+
+	// The length, in UTF-8 bytes, of the longest word
+	maxLength int
+}
+
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		words: NewSet[string](),
+	}
+}
+
+// Read a word list, with one word per line. Blank lines, and lines
+// starting with "#", are ignored.
+func LoadDictionary(r io.Reader) (*Dictionary, error) {
+	d := NewDictionary()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		d.Add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Read a word list from a file; see LoadDictionary.
+func LoadDictionaryFile(filename string) (*Dictionary, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return LoadDictionary(fh)
+}
+
+// Add words. They are normalized the same way ParseGraphemeStacks
+// normalizes its input.
+func (s *Dictionary) Add(words ...string) {
+	for _, word := range words {
+		word = norm.NFC.String(word)
+		s.words.Add(word)
+		if len(word) > s.maxLength {
+			s.maxLength = len(word)
+		}
+	}
+}
+
+// Is the word in the dictionary? It is normalized as Add normalizes
+// words.
+func (s *Dictionary) Has(word string) bool {
+	return s.words.Has(norm.NFC.String(word))
+}
+
+func (s *Dictionary) Len() int {
+	return s.words.Len()
+}
+
+// A span of text found by the WordSegmenter
+type Word struct {
+	// The UTF-8 string in this word
+	Text string
+
+	// The clusters in this word
+	Clusters []GStackCluster
+
+	// Was the word found in the Dictionary? If not, it is a
+	// run of clusters which could not be matched to any word.
+	InDictionary bool
+}
+
+// Segments text into words, with maximal matching: of all the ways to
+// break the text into dictionary words, it picks the one that leaves
+// the fewest clusters unmatched, and then the one with the fewest words.
+// Words can only begin and end on GStackCluster boundaries, so a
+// dictionary word that would split a cluster is never matched.
+type WordSegmenter struct {
+	Dictionary *Dictionary
+}
+
+// The best way found to segment the clusters up to a position
+type wordSegmentPath struct {
+	reached      bool
+	unknown      int
+	words        int
+	prev         int
+	inDictionary bool
+}
+
+func (s *wordSegmentPath) betterThan(other *wordSegmentPath) bool {
+	if !other.reached {
+		return true
+	}
+	if s.unknown != other.unknown {
+		return s.unknown < other.unknown
+	}
+	return s.words < other.words
+}
+
+func (s *WordSegmenter) SegmentGStackClusters(input []GStackCluster) []Word {
+	// offsets[i] is the position in the text where cluster i begins
+	offsets := make([]int, len(input)+1)
+	var sb strings.Builder
+	for i, c := range input {
+		offsets[i] = sb.Len()
+		sb.WriteString(c.Text)
+	}
+	offsets[len(input)] = sb.Len()
+	text := sb.String()
+
+	paths := make([]wordSegmentPath, len(input)+1)
+	paths[0].reached = true
+
+	for i := 0; i < len(input); i++ {
+		if !paths[i].reached {
+			continue
+		}
+
+		// Fall back to a single cluster
+		candidate := wordSegmentPath{
+			reached: true,
+			unknown: paths[i].unknown + 1,
+			words:   paths[i].words + 1,
+			prev:    i,
+		}
+		if candidate.betterThan(&paths[i+1]) {
+			paths[i+1] = candidate
+		}
+
+		if !input[i].IsThai || s.Dictionary == nil {
+			continue
+		}
+		for j := i + 1; j <= len(input); j++ {
+			if offsets[j]-offsets[i] > s.Dictionary.maxLength {
+				break
+			}
+			// The clusters' text is already normalized
+			if !s.Dictionary.words.Has(text[offsets[i]:offsets[j]]) {
+				continue
+			}
+			candidate := wordSegmentPath{
+				reached:      true,
+				unknown:      paths[i].unknown,
+				words:        paths[i].words + 1,
+				prev:         i,
+				inDictionary: true,
+			}
+			if candidate.betterThan(&paths[j]) {
+				paths[j] = candidate
+			}
+		}
+	}
+
+	// Walk the path backwards
+	type span struct {
+		start, end int
+		hit        bool
+	}
+	spans := make([]span, 0, paths[len(input)].words)
+	for j := len(input); j > 0; j = paths[j].prev {
+		spans = append(spans, span{paths[j].prev, j, paths[j].inDictionary})
+	}
+
+	// Reverse them, and merge neighboring unknown clusters into runs,
+	// keeping Thai and non-Thai runs apart.
+	words := make([]Word, 0, len(spans))
+	runStart := 0
+	for k := len(spans) - 1; k >= 0; k-- {
+		sp := spans[k]
+		if !sp.hit && len(words) > 0 {
+			last := &words[len(words)-1]
+			if !last.InDictionary &&
+				input[runStart].IsThai == input[sp.start].IsThai {
+				last.Text = text[offsets[runStart]:offsets[sp.end]]
+				last.Clusters = input[runStart:sp.end]
+				continue
+			}
+		}
+		runStart = sp.start
+		words = append(words, Word{
+			Text:         text[offsets[sp.start]:offsets[sp.end]],
+			Clusters:     input[sp.start:sp.end],
+			InDictionary: sp.hit,
+		})
+	}
+	return words
+}
@@ -0,0 +1,84 @@
+package paasaathai
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func segmentWords(dict *Dictionary, input string) []Word {
+	gcp := NewGStackClusterParser()
+	ws := WordSegmenter{Dictionary: dict}
+	return ws.SegmentGStackClusters(gcp.ParseGraphemeStacks(ParseGraphemeStacks(input)))
+}
+
+func (s *MySuite) TestLoadDictionary(c *C) {
+	dict, err := LoadDictionary(strings.NewReader("# words\nฉัน\n\n  กิน  \nข้าว\n"))
+	c.Assert(err, IsNil)
+	c.Check(dict.Len(), Equals, 3)
+	c.Check(dict.Has("กิน"), Equals, true)
+	c.Check(dict.Has("# words"), Equals, false)
+}
+
+func (s *MySuite) TestDictionaryNormalized(c *C) {
+	// The tone mark is typed before the lower vowel
+	unordered := "\u0e01\u0e48\u0e38"
+	dict := NewDictionary()
+	dict.Add(unordered)
+	c.Check(dict.Has(unordered), Equals, true)
+	c.Check(dict.Has("\u0e01\u0e38\u0e48"), Equals, true)
+}
+
+func (s *MySuite) TestWordSegmenter01(c *C) {
+	dict := NewDictionary()
+	dict.Add("ฉัน", "กิน", "ข้าว", "ข้า")
+
+	words := segmentWords(dict, "ฉันกินข้าว")
+	c.Assert(len(words), Equals, 3)
+	c.Check(words[0].Text, Equals, "ฉัน")
+	c.Check(words[1].Text, Equals, "กิน")
+	c.Check(words[2].Text, Equals, "ข้าว")
+	for _, w := range words {
+		c.Check(w.InDictionary, Equals, true)
+	}
+}
+
+// Fewer words are better
+func (s *MySuite) TestWordSegmenter02(c *C) {
+	dict := NewDictionary()
+	dict.Add("ไป", "ร", "ษณีย์", "ไปรษณีย์")
+
+	words := segmentWords(dict, "ไปรษณีย์")
+	c.Assert(len(words), Equals, 1)
+	c.Check(words[0].Text, Equals, "ไปรษณีย์")
+}
+
+// Unknown words fall back to runs of clusters
+func (s *MySuite) TestWordSegmenterUnknown(c *C) {
+	dict := NewDictionary()
+	dict.Add("ฉัน", "กิน")
+
+	words := segmentWords(dict, "ฉันกินปลาทู and ฉัน")
+	c.Assert(len(words), Equals, 5)
+	c.Check(words[0].Text, Equals, "ฉัน")
+	c.Check(words[1].Text, Equals, "กิน")
+	c.Check(words[2].Text, Equals, "ปลาทู")
+	c.Check(words[2].InDictionary, Equals, false)
+	c.Check(len(words[2].Clusters), Equals, 3)
+	c.Check(words[3].Text, Equals, " and ")
+	c.Check(words[3].InDictionary, Equals, false)
+	c.Check(words[4].Text, Equals, "ฉัน")
+	c.Check(words[4].InDictionary, Equals, true)
+}
+
+// A dictionary word that would split a cluster can't be matched
+func (s *MySuite) TestWordSegmenterClusterBoundary(c *C) {
+	dict := NewDictionary()
+	// เก is not a word by itself in "เกาะ"; it would split the cluster
+	dict.Add("เก", "าะ")
+
+	words := segmentWords(dict, "เกาะ")
+	c.Assert(len(words), Equals, 1)
+	c.Check(words[0].Text, Equals, "เกาะ")
+	c.Check(words[0].InDictionary, Equals, false)
+}